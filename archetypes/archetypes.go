@@ -16,11 +16,12 @@ var (
 		components.Player,
 		components.Animation,
 		components.Object,
-		components.CollistionPlayer,
+		components.Collider,
 		components.Velocity,
 		components.AttackVector,
 		components.Shooter,
 		components.Health,
+		components.Inventory,
 	)
 
 	Enemy = NewArchetype(
@@ -28,33 +29,24 @@ var (
 		tags.Enemy,
 		components.Enemy,
 		components.AI,
+		components.PathFollow,
 		components.Animation,
 		components.Object,
-		components.CollistionPlayer,
+		components.Collider,
 		components.Velocity,
 		components.AttackVector,
 		components.Shooter,
 		components.Health,
-	)
-
-	Bullet = NewArchetype(
-		layers.Interactables,
-		tags.Bullet,
-		components.Bullet,
-		components.Object,
-		components.CollistionBullet,
-		components.Velocity,
 		components.Despawnable,
-		components.Animation,
 	)
 
-	BouncerBullet = NewArchetype(
+	Bullet = NewArchetype(
 		layers.Interactables,
 		tags.Bullet,
 		components.Bullet,
 		components.Object,
+		components.Collider,
 		components.Velocity,
-		components.CollisionBouncer,
 		components.Despawnable,
 		components.Animation,
 	)
@@ -64,6 +56,7 @@ var (
 		tags.Wall,
 		components.Block,
 		components.Object,
+		components.Collider,
 		components.Animation,
 	)
 
@@ -82,6 +75,21 @@ var (
 		components.Camera,
 	)
 
+	Clock = NewArchetype(
+		layers.System,
+		components.Clock,
+	)
+
+	Input = NewArchetype(
+		layers.System,
+		components.Input,
+	)
+
+	Random = NewArchetype(
+		layers.System,
+		components.Random,
+	)
+
 	WeaponSprite = NewArchetype(
 		layers.Interactables,
 		tags.WeaponSprite,
@@ -91,6 +99,62 @@ var (
 		components.Object,
 	)
 
+	WeaponPickup = NewArchetype(
+		layers.Interactables,
+		tags.Pickup,
+		components.Pickup,
+		components.Animation,
+		components.Object,
+		components.Collider,
+		components.Trigger,
+		components.Despawnable,
+	)
+
+	Barrel = NewArchetype(
+		layers.Architecture,
+		components.Explosive,
+		components.Health,
+		components.Object,
+		components.Collider,
+		components.Animation,
+		components.Despawnable,
+	)
+
+	Exit = NewArchetype(
+		layers.Architecture,
+		tags.Exit,
+		components.Object,
+		components.Collider,
+		components.Trigger,
+	)
+
+	Trigger = NewArchetype(
+		layers.Architecture,
+		components.Trigger,
+		components.Object,
+		components.Collider,
+	)
+
+	Room = NewArchetype(
+		layers.Architecture,
+		components.Trigger,
+		components.Room,
+		components.Object,
+		components.Collider,
+	)
+
+	Tracer = NewArchetype(
+		layers.FX,
+		components.Tracer,
+		components.Despawnable,
+	)
+
+	Noise = NewArchetype(
+		layers.System,
+		components.Noise,
+		components.Despawnable,
+	)
+
 	ParticleSprite = NewArchetype(
 		layers.FX,
 		tags.Particle,
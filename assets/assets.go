@@ -3,6 +3,8 @@ package assets
 import (
 	"embed"
 	"encoding/json"
+	"os"
+	"strings"
 )
 
 func Load() error {
@@ -16,9 +18,24 @@ func Load() error {
 	return nil
 }
 
-//go:embed img/*.png config/*.json
+//go:embed img/*.png config/*.json levels/*.json
 var fs embed.FS
 
+// ReadLevel returns a Tiled level, either one of the embedded levels by name
+// or a file on disk when given a path ending in .json.
+func ReadLevel(name string) ([]byte, error) {
+	if strings.HasSuffix(name, ".json") {
+		return os.ReadFile(name)
+	}
+
+	return fs.ReadFile("levels/" + name + ".json")
+}
+
+// MustRead returns an embedded file, like config/sprites.json.
+func MustRead(name string) []byte {
+	return mustRead(name)
+}
+
 func mustRead(name string) []byte {
 	b, err := fs.ReadFile(name)
 	if err != nil {
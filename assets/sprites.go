@@ -52,6 +52,11 @@ func GetAnimation(name string) *ganim8.Animation {
 	return animations[name].Clone()
 }
 
+func HasAnimation(name string) bool {
+	_, ok := animations[name]
+	return ok
+}
+
 func loadSprites(cfg *spriteConfig) {
 	for _, s := range cfg.Sprites {
 		b := mustRead(s.File)                             //load from file
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/AndriiPets/FishGame/utils"
+)
+
+func main() {
+	defaults := utils.DefaultMapOptions()
+
+	seed := flag.Int64("seed", 1, "first seed to generate")
+	count := flag.Int("count", 1, "number of consecutive seeds to generate")
+	genType := flag.String("type", string(defaults.Type), "generation type (bsp, drunk, random)")
+	roomSize := flag.Int("room-size", defaults.RoomSize, "room size")
+	splits := flag.Int("splits", defaults.SplitCount, "bsp split count")
+	hops := flag.Int("hops", defaults.HopLimit, "hop limit from the start room")
+	budget := flag.Int("budget", defaults.EnemyBudget, "enemy budget per floor")
+	pickups := flag.Float64("pickups", defaults.PickupDensity, "weapon pickup density")
+	traps := flag.Float64("traps", defaults.TrapDensity, "spike trap density")
+	destructible := flag.Float64("destructible", defaults.DestructibleDensity, "destructible wall density")
+	barrels := flag.Float64("barrels", defaults.BarrelDensity, "explosive barrel density")
+	out := flag.String("out", "", "write the layouts to this file instead of stdout")
+	pin := flag.String("pin", "", "append the generated seeds and options to this favourites file")
+	flag.Parse()
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	opts := utils.MapOptions{
+		Type:                utils.GenerationType(*genType),
+		RoomSize:            *roomSize,
+		SplitCount:          *splits,
+		HopLimit:            *hops,
+		EnemyBudget:         *budget,
+		EnemyTypes:          defaults.EnemyTypes,
+		PickupDensity:       *pickups,
+		TrapDensity:         *traps,
+		DestructibleDensity: *destructible,
+		BarrelDensity:       *barrels,
+	}
+
+	failed := 0
+
+	for i := 0; i < *count; i++ {
+		opts.Seed = *seed + int64(i)
+
+		world := utils.NewWorldMap()
+		if err := world.GenerateMap(opts); err != nil {
+			fmt.Fprintf(w, "seed: %d error: %v\n\n", opts.Seed, err)
+			failed++
+			continue
+		}
+
+		kinds := map[utils.SpawnKind]int{}
+		for _, s := range world.Spawns {
+			kinds[s.Kind]++
+		}
+
+		fmt.Fprintf(w, "seed: %d type: %s enemies: %d rooms: %d traps: %d destructible: %d barrels: %d\n%s\n", opts.Seed, opts.Type,
+			kinds[utils.SpawnEnemy], kinds[utils.SpawnRoom], kinds[utils.SpawnTrap], len(world.Destructible), kinds[utils.SpawnBarrel], world)
+
+		if *pin != "" {
+			if err := pinSeed(*pin, opts); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+
+	if failed == *count {
+		os.Exit(1)
+	}
+}
+
+func pinSeed(path string, opts utils.MapOptions) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "-seed %d -type %s -room-size %d -splits %d -hops %d -budget %d -pickups %g -traps %g -destructible %g -barrels %g\n",
+		opts.Seed, opts.Type, opts.RoomSize, opts.SplitCount, opts.HopLimit, opts.EnemyBudget, opts.PickupDensity, opts.TrapDensity, opts.DestructibleDensity, opts.BarrelDensity)
+	return err
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/input"
+	"github.com/AndriiPets/FishGame/replay"
+	"github.com/AndriiPets/FishGame/scenes"
+	"github.com/AndriiPets/FishGame/tags"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/features/math"
+)
+
+var scripts = map[string]input.Source{
+	"idle": input.Idle,
+	//walk in a square while shooting to the right
+	"strafe": input.Script(func(tick int) input.State {
+		dirs := []math.Vec2{
+			math.NewVec2(1, 0),
+			math.NewVec2(0, 1),
+			math.NewVec2(-1, 0),
+			math.NewVec2(0, -1),
+		}
+
+		return input.State{
+			Move: dirs[(tick/60)%len(dirs)],
+			Aim:  math.NewVec2(1, 0),
+			Fire: tick%2 == 0,
+		}
+	}),
+}
+
+func main() {
+	ticks := flag.Int("ticks", 3600, "number of ticks to simulate")
+	script := flag.String("script", "idle", "input script to feed the player (idle, strafe)")
+	seed := flag.Int64("seed", 1, "simulation seed")
+	replayPath := flag.String("replay", "", "feed the player from a replay file, overrides -script and -seed")
+	flag.StringVar(&config.C.Level, "level", config.C.Level, "tiled level to play, embedded level name or path to a .json file")
+	flag.StringVar(&config.C.WeaponsFile, "weapons", config.C.WeaponsFile, "json file with weapon definitions merged over the embedded ones")
+	flag.Parse()
+
+	if err := scenes.CheckLevel(); err != nil {
+		log.Fatal(err)
+	}
+
+	source, ok := scripts[*script]
+	if !ok {
+		log.Fatalf("unknown script: %s", *script)
+	}
+
+	if *replayPath != "" {
+		player, err := replay.Open(*replayPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if player.Header.Title {
+			log.Fatalf("%s starts on the title menu, headless runs have no menus to play it through", *replayPath)
+		}
+		source = player
+		*seed = player.Header.Seed
+		*ticks = player.Ticks()
+	}
+
+	scene := scenes.NewHeadlessScene(source, *seed)
+	scene.Run(*ticks)
+	if err := scene.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	world := scene.ECS().World
+
+	player := components.Player.MustFirst(world)
+	playerObj := components.Object.Get(player)
+	playerHealth := components.Health.Get(player)
+
+	enemiesAlive := 0
+	tags.Enemy.Each(world, func(e *donburi.Entry) {
+		if !components.Health.Get(e).Dead {
+			enemiesAlive++
+		}
+	})
+
+	fmt.Printf("seed: %d ticks: %d\n", *seed, *ticks)
+	fmt.Printf("player position: %.1f, %.1f\n", playerObj.Position.X, playerObj.Position.Y)
+	fmt.Printf("player health: %d dead: %t\n", playerHealth.Ammount, playerHealth.Dead)
+	fmt.Printf("enemies alive: %d\n", enemiesAlive)
+
+	summary := scene.Summary()
+	fmt.Printf("run: floor %d, %d kills in %d ticks (seed %d)\n", summary.Depth+1, summary.Kills, summary.Ticks, summary.Seed)
+}
@@ -1,24 +1,45 @@
 package components
 
 import (
-	"github.com/quasilyte/pathing"
+	"github.com/AndriiPets/FishGame/resources"
 	"github.com/yohamta/donburi"
-)
-
-type AIType int
-
-const (
-	AITypeShooter AIType = iota
-	AITypeBrawler
+	"github.com/yohamta/donburi/features/math"
 )
 
 type AIData struct {
-	AIType            AIType
+	AIType            resources.AIType
 	VisionRadius      float64
 	AgressionModifier int
 	ActionModifier    int
-	Path              pathing.BuildPathResult
-	PathCurrent       pathing.Direction
+	Steer             math.Vec2 //moves this way instead of following the path when set
+	Board             Blackboard
+
+	//perception
+	VisionAngle float64   //degrees of the vision cone, 0 sees all around
+	Memory      float64   //seconds a lost player is remembered
+	Facing      math.Vec2 //where the enemy is looking
+	Sees        bool      //the player is in view this tick
+	Alerted     bool      //knows about the player, seen or told by an ally
+
+	//brawler dash
+	Dashing  bool
+	DashVec  math.Vec2
+	DashTick int //tick the last dash started
+
+	//sniper telegraph
+	Aiming    bool
+	AimTick   int //tick the sniper started lining up its shot
+	AimTarget math.Vec2
+}
+
+// Blackboard is what an enemy's behaviour tree remembers between ticks.
+type Blackboard struct {
+	LastSeen     math.Vec2 //where the player was last seen
+	LastSeenTick int
+	HasLastSeen  bool
+	PatrolTo     math.Vec2
+	PatrolTick   int //tick the current patrol point was picked
+	HasPatrol    bool
 }
 
 var AI = donburi.NewComponentType[AIData]()
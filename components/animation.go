@@ -24,6 +24,8 @@ type AnimationData struct {
 	PlaybackSpeed float64
 	Type          AnimationType
 	Ease          *gween.Tween
+	Fade          float64    //0 is opaque, 1 is invisible
+	Tint          [3]float64 //color scale, unset draws the sprite as is
 }
 
 var Animation = donburi.NewComponentType[AnimationData]()
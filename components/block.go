@@ -8,7 +8,8 @@ import (
 
 type BlockData struct {
 	Type         BlockType
-	Destructable bool
+	Destructable bool //takes damage and crumbles once its health is gone
+	Damaged      bool
 }
 
 type BlockType string
@@ -24,6 +25,12 @@ func (b *BlockData) String() string {
 	return string(b.Type)
 }
 
+// Animation is the sprite of the block, damaged blocks show cracks when their
+// type has a sprite for it.
 func (b *BlockData) Animation() *ganim8.Animation {
+	if b.Damaged && assets.HasAnimation(b.String()+"_damaged") {
+		return assets.GetAnimation(b.String() + "_damaged")
+	}
+
 	return assets.GetAnimation(b.String() + "_default")
 }
@@ -7,11 +7,30 @@ import (
 )
 
 type BulletData struct {
-	IsDead bool
+	IsDead    bool
+	Sprite    string
+	SpawnTick int
+	Lifetime  float64 //seconds before the bullet despawns
+	Bounces   int     //walls left to bounce off
+
+	Damage       int
+	Knockback    float64
+	Owner        donburi.Entity
+	Faction      Faction
+	FriendlyFire bool //hits actors of its own faction
+}
+
+// CanHit reports whether the bullet damages the target entity. Bullets never
+// hit whoever fired them and skip allies unless the weapon allows friendly fire.
+func (db *BulletData) CanHit(target *donburi.Entry) bool {
+	return CanDamage(db.Owner, db.Faction, db.FriendlyFire, target)
 }
 
 var Bullet = donburi.NewComponentType[BulletData]()
 
 func (db *BulletData) Animation() *ganim8.Animation {
-	return assets.GetAnimation("bullet_default")
+	if db.Sprite == "" {
+		return assets.GetAnimation("bullet_default")
+	}
+	return assets.GetAnimation(db.Sprite)
 }
@@ -13,6 +13,7 @@ type CameraData struct {
 	CursorX  float64
 	CursorY  float64
 	Recoil   math.Vec2
+	Shake    float64 //pixels the view is thrown around by, flips and dies down every tick
 	Flash    bool
 }
 
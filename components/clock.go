@@ -0,0 +1,15 @@
+package components
+
+import "github.com/yohamta/donburi"
+
+type ClockData struct {
+	Tick  int
+	Delta float64 //fixed step in seconds
+}
+
+var Clock = donburi.NewComponentType[ClockData]()
+
+// Since returns the seconds elapsed since the given tick.
+func (c *ClockData) Since(tick int) float64 {
+	return float64(c.Tick-tick) * c.Delta
+}
@@ -2,12 +2,56 @@ package components
 
 import "github.com/yohamta/donburi"
 
-type CollisionType struct {
-	Type string
-}
+// CollisionLayer is a set of the kinds of objects in the collision space.
+type CollisionLayer uint16
 
-var (
-	CollistionPlayer = donburi.NewComponentType[CollisionType]()
-	CollistionBullet = donburi.NewComponentType[CollisionType]()
-	CollisionBouncer = donburi.NewComponentType[CollisionType]()
+const (
+	LayerWall CollisionLayer = 1 << iota
+	LayerPlayer
+	LayerEnemy
+	LayerPlayerProjectile
+	LayerEnemyProjectile
+	LayerPickup
+	LayerTrigger
+	LayerProp
 )
+
+var layerTags = map[CollisionLayer]string{
+	LayerWall:             "wall",
+	LayerPlayer:           "player",
+	LayerEnemy:            "enemy",
+	LayerPlayerProjectile: "player_projectile",
+	LayerEnemyProjectile:  "enemy_projectile",
+	LayerPickup:           "pickup",
+	LayerTrigger:          "trigger",
+	LayerProp:             "prop",
+}
+
+// Tag is the resolv tag objects on the layer carry, so space cells can be
+// filtered by layer without looking up their entries.
+func (l CollisionLayer) Tag() string {
+	return layerTags[l]
+}
+
+// ColliderData puts an object on a layer, Mask holds the layers it runs into
+// while it moves. An empty layer is never run into.
+type ColliderData struct {
+	Layer CollisionLayer
+	Mask  CollisionLayer
+}
+
+var Collider = donburi.NewComponentType[ColliderData]()
+
+// ProjectileCollider is the layer and mask of a projectile fired by the faction,
+// friendly fire lets it run into actors of both sides.
+func ProjectileCollider(faction Faction, friendlyFire bool) ColliderData {
+	c := ColliderData{Layer: LayerEnemyProjectile, Mask: LayerWall | LayerProp | LayerPlayer}
+	if faction == FactionPlayer {
+		c = ColliderData{Layer: LayerPlayerProjectile, Mask: LayerWall | LayerProp | LayerEnemy}
+	}
+	if friendlyFire || faction == FactionNone {
+		c.Mask |= LayerPlayer | LayerEnemy
+	}
+
+	return c
+}
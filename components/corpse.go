@@ -0,0 +1,10 @@
+package components
+
+import "github.com/yohamta/donburi"
+
+type CorpseData struct {
+	DeathTick int
+	Persist   bool //stays until the level ends, used for the player
+}
+
+var Corpse = donburi.NewComponentType[CorpseData]()
@@ -0,0 +1,14 @@
+package components
+
+import "github.com/yohamta/donburi"
+
+// ExplosiveData blows the entry up once it is destroyed, hurting everything
+// with health inside Radius. Damage and knockback fall off towards the edge.
+type ExplosiveData struct {
+	Radius    float64
+	Damage    int
+	Knockback float64
+	Exploded  bool
+}
+
+var Explosive = donburi.NewComponentType[ExplosiveData]()
@@ -0,0 +1,39 @@
+package components
+
+import "github.com/yohamta/donburi"
+
+type Faction string
+
+const (
+	FactionNone   Faction = ""
+	FactionPlayer Faction = "player"
+	FactionEnemy  Faction = "enemy"
+)
+
+// FactionOf returns the team an entity fights for.
+func FactionOf(e *donburi.Entry) Faction {
+	switch {
+	case e.HasComponent(Player):
+		return FactionPlayer
+	case e.HasComponent(Enemy):
+		return FactionEnemy
+	}
+	return FactionNone
+}
+
+// CanDamage reports whether an attack by owner of the given faction hurts the
+// target. Nobody hurts themselves and allies are spared unless friendlyFire.
+func CanDamage(owner donburi.Entity, faction Faction, friendlyFire bool, target *donburi.Entry) bool {
+	if target.Entity() == owner {
+		return false
+	}
+	if faction.Friendly(FactionOf(target)) {
+		return friendlyFire
+	}
+	return true
+}
+
+// Friendly reports whether two factions are on the same side.
+func (f Faction) Friendly(other Faction) bool {
+	return f != FactionNone && f == other
+}
@@ -2,7 +2,6 @@ package components
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/yohamta/donburi"
 )
@@ -13,7 +12,7 @@ type HealthData struct {
 	DeathLock bool
 
 	Hit      bool
-	HitTime  time.Time
+	HitTime  int //tick of the last hit
 	Cooldown float64
 }
 
@@ -0,0 +1,59 @@
+package components
+
+import (
+	"github.com/AndriiPets/FishGame/input"
+	"github.com/yohamta/donburi"
+)
+
+type InputData struct {
+	Source   input.Source
+	Current  input.State
+	Previous input.State
+}
+
+var Input = donburi.NewComponentType[InputData]()
+
+// SwapPressed reports whether weapon swap was pressed on this tick.
+func (i *InputData) SwapPressed() bool {
+	return i.Current.SwapWeapon && !i.Previous.SwapWeapon
+}
+
+// CyclePressed returns the direction the weapon selection moves on this tick,
+// 1 for the next weapon, -1 for the previous one and 0 for none.
+func (i *InputData) CyclePressed() int {
+	switch {
+	case i.SwapPressed() || i.Current.Scroll > 0:
+		return 1
+	case i.Current.PrevWeapon && !i.Previous.PrevWeapon || i.Current.Scroll < 0:
+		return -1
+	}
+	return 0
+}
+
+// SlotPressed returns the inventory slot picked on this tick, 1 based, or 0.
+func (i *InputData) SlotPressed() int {
+	if i.Current.Slot != i.Previous.Slot {
+		return i.Current.Slot
+	}
+	return 0
+}
+
+// DropPressed reports whether drop weapon was pressed on this tick.
+func (i *InputData) DropPressed() bool {
+	return i.Current.Drop && !i.Previous.Drop
+}
+
+// FirePressed reports whether fire was pressed on this tick.
+func (i *InputData) FirePressed() bool {
+	return i.Current.Fire && !i.Previous.Fire
+}
+
+// PausePressed reports whether pause was pressed on this tick.
+func (i *InputData) PausePressed() bool {
+	return i.Current.Pause && !i.Previous.Pause
+}
+
+// ReloadPressed reports whether reload was pressed on this tick.
+func (i *InputData) ReloadPressed() bool {
+	return i.Current.Reload && !i.Previous.Reload
+}
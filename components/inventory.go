@@ -0,0 +1,110 @@
+package components
+
+import (
+	"github.com/AndriiPets/FishGame/resources"
+	"github.com/yohamta/donburi"
+)
+
+// WeaponSlot is a carried weapon together with its own ammo.
+type WeaponSlot struct {
+	Type    string
+	Ammo    int
+	Reserve int
+}
+
+// NewWeaponSlot returns the named weapon with a full magazine and its starting reserve.
+func NewWeaponSlot(name string) WeaponSlot {
+	weapon := resources.WeaponMap[name]
+	return WeaponSlot{Type: name, Ammo: weapon.Magazine, Reserve: weapon.Reserve}
+}
+
+type InventoryData struct {
+	Slots    []WeaponSlot
+	Current  int
+	Capacity int
+}
+
+var Inventory = donburi.NewComponentType[InventoryData]()
+
+// Add stores a weapon and returns its slot. Ammo of a weapon already carried
+// goes to its reserve. ok is false when the inventory is full.
+func (inv *InventoryData) Add(slot WeaponSlot) (int, bool) {
+	for i := range inv.Slots {
+		if inv.Slots[i].Type == slot.Type {
+			inv.Slots[i].Reserve += slot.Ammo + slot.Reserve
+			return i, true
+		}
+	}
+
+	if len(inv.Slots) >= inv.Capacity {
+		return -1, false
+	}
+
+	inv.Slots = append(inv.Slots, slot)
+	return len(inv.Slots) - 1, true
+}
+
+// Has reports whether a weapon of this type is carried.
+func (inv *InventoryData) Has(name string) bool {
+	for _, s := range inv.Slots {
+		if s.Type == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Save writes the shooter's ammo back into the current slot.
+func (inv *InventoryData) Save(sd *ShooterData) {
+	if inv.Current < 0 || inv.Current >= len(inv.Slots) {
+		return
+	}
+	inv.Slots[inv.Current] = WeaponSlot{Type: sd.Type, Ammo: sd.Ammo, Reserve: sd.Reserve}
+}
+
+// Equip saves the current weapon and hands the shooter the weapon in slot i.
+func (inv *InventoryData) Equip(i int, sd *ShooterData) {
+	if i < 0 || i >= len(inv.Slots) {
+		return
+	}
+
+	inv.Save(sd)
+	inv.Current = i
+
+	slot := inv.Slots[i]
+	sd.SetWeapon(slot.Type)
+	sd.Ammo = slot.Ammo
+	sd.Reserve = slot.Reserve
+}
+
+// Cycle equips the next weapon in dir, wrapping around.
+func (inv *InventoryData) Cycle(dir int, sd *ShooterData) {
+	n := len(inv.Slots)
+	if n < 2 {
+		return
+	}
+	inv.Equip(((inv.Current+dir)%n+n)%n, sd)
+}
+
+// Remove takes the current weapon out of the inventory and equips its
+// neighbour. The last weapon can not be removed.
+func (inv *InventoryData) Remove(sd *ShooterData) (WeaponSlot, bool) {
+	if len(inv.Slots) < 2 {
+		return WeaponSlot{}, false
+	}
+
+	inv.Save(sd)
+	removed := inv.Slots[inv.Current]
+	inv.Slots = append(inv.Slots[:inv.Current], inv.Slots[inv.Current+1:]...)
+
+	next := inv.Current
+	if next >= len(inv.Slots) {
+		next = len(inv.Slots) - 1
+	}
+
+	//nothing to save, the slot is gone
+	inv.Current = -1
+	inv.Equip(next, sd)
+
+	return removed, true
+}
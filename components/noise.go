@@ -0,0 +1,16 @@
+package components
+
+import (
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/features/math"
+)
+
+// NoiseData marks where a noise was heard so the ai debug view can draw it.
+type NoiseData struct {
+	Position  math.Vec2
+	Radius    float64
+	SpawnTick int
+	Duration  float64 //seconds the ring stays visible
+}
+
+var Noise = donburi.NewComponentType[NoiseData]()
@@ -0,0 +1,21 @@
+package components
+
+import (
+	"github.com/AndriiPets/FishGame/utils"
+	"github.com/quasilyte/pathing"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/features/math"
+)
+
+// PathFollowData is the route an enemy walks along. It is kept between ticks
+// and only planned again when the goal moves to another cell or the plan gets old.
+type PathFollowData struct {
+	Waypoints []math.Vec2       //object positions still to reach, the first one is next
+	Goal      pathing.GridCoord //cell the route leads to
+	PlanTick  int               //tick the route was planned
+	Planned   bool
+	Active    bool               //the ai walks the route this tick
+	Request   *utils.PathRequest //path query waiting for its turn
+}
+
+var PathFollow = donburi.NewComponentType[PathFollowData]()
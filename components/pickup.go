@@ -0,0 +1,11 @@
+package components
+
+import "github.com/yohamta/donburi"
+
+type PickupData struct {
+	Weapon    WeaponSlot
+	SpawnTick int
+	Delay     float64 //seconds before it can be picked up, keeps drops from bouncing back
+}
+
+var Pickup = donburi.NewComponentType[PickupData]()
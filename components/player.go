@@ -2,10 +2,10 @@ package components
 
 import (
 	//"github.com/solarlune/resolv"
-	"time"
 
 	"github.com/AndriiPets/FishGame/assets"
 	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/features/math"
 	"github.com/yohamta/ganim8/v2"
 )
 
@@ -13,8 +13,9 @@ type PlayerData struct {
 	FacingRight   bool
 	IsDashing     bool
 	State         PlayerState
-	DashTimer     time.Time
-	ParticleTimer time.Time
+	DashTimer     int //tick the dash started
+	DashVec       math.Vec2
+	ParticleTimer int
 	ParticleSpawn bool
 }
 
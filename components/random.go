@@ -0,0 +1,10 @@
+package components
+
+import (
+	"math/rand"
+
+	"github.com/yohamta/donburi"
+)
+
+// Random is the seeded random source shared by all gameplay systems.
+var Random = donburi.NewComponentType[rand.Rand]()
@@ -1,9 +1,8 @@
 package components
 
 import (
-	"time"
-
 	"github.com/AndriiPets/FishGame/assets"
+	"github.com/AndriiPets/FishGame/resources"
 	"github.com/yohamta/donburi"
 	"github.com/yohamta/donburi/features/math"
 	"github.com/yohamta/ganim8/v2"
@@ -14,17 +13,59 @@ import (
 type ShooterData struct {
 	Type           string
 	Fire           bool
-	FireTime       time.Time
+	FireTime       int //tick of the last shot
 	Cooldown       float64
 	CanFire        bool
 	Position       math.Vec2
 	HolderPosition math.Vec2
 	WeaponFlash    bool
 	HoldRange      float64
+
+	Charging    bool //charge weapon is being held
+	ChargeStart int  //tick the charge began
+
+	TriggerHeld bool //fire was requested on the previous tick
+	BurstLeft   int  //shots still owed by the current burst
+
+	Ammo         int  //rounds in the magazine
+	Reserve      int  //spare rounds
+	InfiniteAmmo bool //reloads never draw from the reserve, for enemies
+
+	SpriteType  string //weapon the holder's weapon sprite currently shows
+	Reload      bool
+	Reloading   bool
+	ReloadStart int //tick the reload began
 }
 
 var Shooter = donburi.NewComponentType[ShooterData]()
 
+// SetWeapon switches to the named weapon with a full magazine and its starting reserve.
+func (sd *ShooterData) SetWeapon(name string) {
+	weapon := resources.WeaponMap[name]
+
+	sd.Type = name
+	sd.Ammo = weapon.Magazine
+	sd.Reserve = weapon.Reserve
+	sd.Charging = false
+	sd.BurstLeft = 0
+	sd.Reload = false
+	sd.Reloading = false
+}
+
+// CanReload reports whether a reload would add any rounds to the magazine.
+func (sd *ShooterData) CanReload() bool {
+	weapon := resources.WeaponMap[sd.Type]
+	if weapon.Magazine == 0 || sd.Reloading || sd.Ammo >= weapon.Magazine {
+		return false
+	}
+	return sd.InfiniteAmmo || sd.Reserve > 0
+}
+
+// Empty reports whether the magazine has run dry.
+func (sd *ShooterData) Empty() bool {
+	return resources.WeaponMap[sd.Type].Magazine > 0 && sd.Ammo <= 0
+}
+
 func (sd *ShooterData) Animation() *ganim8.Animation {
-	return assets.GetAnimation("weapon_" + sd.Type)
+	return assets.GetAnimation(resources.WeaponMap[sd.Type].Sprite)
 }
@@ -0,0 +1,15 @@
+package components
+
+import (
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/features/math"
+)
+
+type TracerData struct {
+	From      math.Vec2
+	To        math.Vec2
+	SpawnTick int
+	Duration  float64 //seconds the line stays visible
+}
+
+var Tracer = donburi.NewComponentType[TracerData]()
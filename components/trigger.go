@@ -0,0 +1,43 @@
+package components
+
+import (
+	"github.com/quasilyte/pathing"
+	"github.com/yohamta/donburi"
+)
+
+// TriggerKind is what a trigger does when something walks into it.
+type TriggerKind string
+
+const (
+	TriggerArea   TriggerKind = "area"   //only publishes its events
+	TriggerRoom   TriggerKind = "room"   //locks the room's doors until its enemies are dead
+	TriggerExit   TriggerKind = "exit"   //takes the player down to the next floor
+	TriggerPickup TriggerKind = "pickup" //hands its weapon to the player
+	TriggerTrap   TriggerKind = "trap"   //hurts whoever steps on it
+	TriggerPrompt TriggerKind = "prompt" //shows a message while the player is inside
+)
+
+// TriggerData is a volume that publishes enter, stay and exit events for the
+// entries on its mask layers that overlap it.
+type TriggerData struct {
+	Kind   TriggerKind
+	Mask   CollisionLayer
+	Text   string //what a prompt says
+	Damage int    //what a trap deals to everything stepping on it
+
+	Inside []donburi.Entity //entries overlapping it at the last update
+}
+
+var Trigger = donburi.NewComponentType[TriggerData]()
+
+// RoomData is a room that locks its doors while the player fights inside.
+type RoomData struct {
+	Doors   []pathing.GridCoord //cells closed while the room is locked, found again on every lock
+	Locked  bool
+	Cleared bool
+
+	Enemies []donburi.Entity //enemies the player got locked in with
+	Blocks  []donburi.Entity //walls standing in the doors
+}
+
+var Room = donburi.NewComponentType[RoomData]()
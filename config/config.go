@@ -5,6 +5,11 @@ type Config struct {
 	ScreenHeight int
 	WorldWidth   int
 	WorldHeigth  int
+	Level        string  //tiled level to play, procedural generation when empty
+	WeaponsFile  string  //weapon definitions merged over the embedded ones
+	CorpseTime   float64 //seconds before a dead enemy despawns
+	ScreenShake  bool
+	Debug        bool //draw the collision grid and ai debug info
 }
 
 var C *Config
@@ -13,6 +18,7 @@ const (
 	MapWidth  int = 80
 	MapHeigth int = 45
 	BlockSize int = 32
+	TickRate  int = 60
 )
 
 func init() {
@@ -21,5 +27,7 @@ func init() {
 		ScreenHeight: 360,
 		WorldWidth:   MapWidth * BlockSize,
 		WorldHeigth:  MapHeigth * BlockSize,
+		CorpseTime:   5,
+		ScreenShake:  true,
 	}
 }
@@ -0,0 +1,13 @@
+package events
+
+import (
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/features/events"
+)
+
+// Death is published once when an actor's health runs out.
+type Death struct {
+	Entry *donburi.Entry
+}
+
+var DeathEvent = events.NewEventType[Death]()
@@ -0,0 +1,13 @@
+package events
+
+import (
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/features/events"
+)
+
+// FloorExit is published when the player steps on the exit of a floor.
+type FloorExit struct {
+	Entry *donburi.Entry
+}
+
+var FloorExitEvent = events.NewEventType[FloorExit]()
@@ -0,0 +1,17 @@
+package events
+
+import (
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/features/events"
+	"github.com/yohamta/donburi/features/math"
+)
+
+// Noise is published when something loud happens, enemies of the source
+// inside the radius hear it.
+type Noise struct {
+	Source   *donburi.Entry
+	Position math.Vec2
+	Radius   float64
+}
+
+var NoiseEvent = events.NewEventType[Noise]()
@@ -8,9 +8,13 @@ import (
 	//"github.com/yohamta/donburi/features/math"
 
 	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/config"
 	//dresolv "github.com/AndriiPets/FishGame/resolv"
 )
 
+// pixels the view is thrown around by when something blows up
+const explosionShake = 10.0
+
 type ScreenShake struct {
 	Type string
 }
@@ -33,6 +37,10 @@ func OnRecoilScreenShake(w donburi.World, event ScreenShake) {
 
 	camera := components.Camera.Get(cameraEntity)
 
+	if !config.C.ScreenShake {
+		return
+	}
+
 	if event.Type == "recoil" {
 
 		playerEntity, _ := components.Player.First(w)
@@ -52,6 +60,10 @@ func OnRecoilScreenShake(w donburi.World, event ScreenShake) {
 		camera.Flash = true
 
 	}
+
+	if event.Type == "explosion" {
+		camera.Shake = explosionShake
+	}
 }
 
 func WeaponSpriteRecoil(w donburi.World, event WeaponRecoil) {
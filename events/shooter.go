@@ -0,0 +1,21 @@
+package events
+
+import (
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/features/events"
+)
+
+// EmptyClick is published when a shooter pulls the trigger on an empty magazine.
+type EmptyClick struct {
+	Entry *donburi.Entry
+}
+
+type Reload struct {
+	Entry *donburi.Entry
+}
+
+var (
+	EmptyClickEvent   = events.NewEventType[EmptyClick]()
+	ReloadStartEvent  = events.NewEventType[Reload]()
+	ReloadFinishEvent = events.NewEventType[Reload]()
+)
@@ -0,0 +1,18 @@
+package events
+
+import (
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/features/events"
+)
+
+// Trigger is an entry entering, staying in or leaving a trigger volume.
+type Trigger struct {
+	Trigger *donburi.Entry
+	Entry   *donburi.Entry
+}
+
+var (
+	TriggerEnterEvent = events.NewEventType[Trigger]()
+	TriggerStayEvent  = events.NewEventType[Trigger]() //every tick the entry is inside, including the one it entered on
+	TriggerExitEvent  = events.NewEventType[Trigger]()
+)
@@ -0,0 +1,40 @@
+package factory
+
+import (
+	"github.com/AndriiPets/FishGame/archetypes"
+	"github.com/AndriiPets/FishGame/assets"
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/config"
+	dresolv "github.com/AndriiPets/FishGame/resolv"
+
+	"github.com/solarlune/resolv"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
+
+const (
+	barrelSize   = 24
+	barrelHealth = 2
+)
+
+// what a barrel does to its surroundings when it goes off
+var barrelBlast = components.ExplosiveData{Radius: 80, Damage: 4, Knockback: 8}
+
+// CreateBarrel places an explosive barrel in the middle of the tile at posX, posY.
+func CreateBarrel(ecs *ecs.ECS, posX, posY float64) *donburi.Entry {
+	barrel := archetypes.Barrel.Spawn(ecs)
+
+	components.Explosive.SetValue(barrel, barrelBlast)
+	components.Health.SetValue(barrel, components.HealthData{Ammount: barrelHealth})
+
+	animation := components.Animation.Get(barrel)
+	animation.Animation = assets.GetAnimation("barrel_default")
+	animation.Type = components.AnimationStatic
+
+	offset := float64(config.BlockSize-barrelSize) / 2
+	obj := resolv.NewObject(posX+offset, posY+offset, barrelSize, barrelSize)
+	dresolv.SetObject(barrel, obj)
+	dresolv.SetCollider(barrel, components.LayerProp, 0)
+
+	return barrel
+}
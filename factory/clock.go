@@ -0,0 +1,38 @@
+package factory
+
+import (
+	"math/rand"
+
+	"github.com/AndriiPets/FishGame/archetypes"
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/input"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
+
+func CreateClock(ecs *ecs.ECS, delta float64) *donburi.Entry {
+	clock := archetypes.Clock.Spawn(ecs)
+
+	components.Clock.SetValue(clock, components.ClockData{
+		Delta: delta,
+	})
+
+	return clock
+}
+
+func CreateInput(ecs *ecs.ECS, source input.Source) *donburi.Entry {
+	in := archetypes.Input.Spawn(ecs)
+
+	components.Input.SetValue(in, components.InputData{
+		Source: source,
+	})
+
+	return in
+}
+
+func CreateRandom(ecs *ecs.ECS, seed int64) *donburi.Entry {
+	random := archetypes.Random.Spawn(ecs)
+	components.Random.Set(random, rand.New(rand.NewSource(seed)))
+
+	return random
+}
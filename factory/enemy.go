@@ -4,15 +4,20 @@ import (
 	"github.com/AndriiPets/FishGame/archetypes"
 	"github.com/AndriiPets/FishGame/components"
 	dresolv "github.com/AndriiPets/FishGame/resolv"
+	"github.com/AndriiPets/FishGame/resources"
 	"github.com/solarlune/resolv"
 	"github.com/tanema/gween"
 	"github.com/tanema/gween/ease"
 	"github.com/yohamta/donburi"
 	"github.com/yohamta/donburi/ecs"
+	"github.com/yohamta/donburi/features/hierarchy"
+	"github.com/yohamta/donburi/features/math"
 )
 
+// CreateEnemy spawns an enemy with the stats of its type from the enemy data.
 func CreateEnemy(ecs *ecs.ECS, posX, posY float64, enemyType components.EnemyType) *donburi.Entry {
 	enemyEntry := archetypes.Enemy.Spawn(ecs)
+	stats := resources.EnemyMap[string(enemyType)]
 
 	//setup initial state
 	enemy := components.Enemy.Get(enemyEntry)
@@ -21,23 +26,27 @@ func CreateEnemy(ecs *ecs.ECS, posX, posY float64, enemyType components.EnemyTyp
 
 	//setup enemy stats
 	health := components.Health.Get(enemyEntry)
-	health.Ammount = 3
+	health.Ammount = stats.Health
 	health.Cooldown = 0.4
 
 	//setup shooter
 	components.Shooter.SetValue(enemyEntry, components.ShooterData{
-		Type:      "enemy_default", //bouncer, //default
-		Fire:      false,
-		CanFire:   true,
-		HoldRange: 10,
+		Fire:         false,
+		CanFire:      true,
+		HoldRange:    stats.HoldRange,
+		InfiniteAmmo: true,
 	})
+	components.Shooter.Get(enemyEntry).SetWeapon(stats.Weapon)
 
 	//setup ai
 	components.AI.SetValue(enemyEntry, components.AIData{
-		AIType:            components.AITypeShooter,
-		VisionRadius:      200,
-		AgressionModifier: 3,
-		ActionModifier:    5,
+		AIType:            stats.AI,
+		VisionRadius:      stats.Vision,
+		AgressionModifier: stats.Aggression,
+		ActionModifier:    stats.Action,
+		VisionAngle:       stats.FOV,
+		Memory:            stats.Memory,
+		Facing:            math.NewVec2(0, 1),
 	})
 
 	//setup animation
@@ -45,11 +54,13 @@ func CreateEnemy(ecs *ecs.ECS, posX, posY float64, enemyType components.EnemyTyp
 	animation.Animation = enemy.Animation()
 	animation.FlipH = false
 	animation.Type = components.AnimationActor
+	animation.Tint = stats.Tint
 
 	//setup enemy object
 	obj := resolv.NewObject(posX, posY, 16, 16)
-	obj.AddTags("damageable")
 	dresolv.SetObject(enemyEntry, obj)
+	//bullets of both sides are run into, the bullet decides if friendly fire hurts
+	dresolv.SetCollider(enemyEntry, components.LayerEnemy, components.LayerWall|components.LayerProp|components.LayerPlayerProjectile|components.LayerEnemyProjectile)
 
 	//setup weapon sprite
 	wSprite := archetypes.WeaponSprite.Spawn(ecs)
@@ -58,6 +69,7 @@ func CreateEnemy(ecs *ecs.ECS, posX, posY float64, enemyType components.EnemyTyp
 
 	wSprite.SetComponent(components.Shooter, enemyEntry.Component(components.Shooter))
 	wSprite.SetComponent(components.AttackVector, enemyEntry.Component(components.AttackVector))
+	hierarchy.SetParent(wSprite, enemyEntry)
 
 	dresolv.SetObject(wSprite, resolv.NewObject(posX, posY, 16, 16))
 	wAnimation.Animation = shooter.Animation()
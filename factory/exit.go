@@ -0,0 +1,24 @@
+package factory
+
+import (
+	"github.com/AndriiPets/FishGame/archetypes"
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/config"
+	dresolv "github.com/AndriiPets/FishGame/resolv"
+
+	"github.com/solarlune/resolv"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
+
+// CreateExit places the stairs down to the next floor.
+func CreateExit(ecs *ecs.ECS, posX, posY float64) *donburi.Entry {
+	exit := archetypes.Exit.Spawn(ecs)
+
+	obj := resolv.NewObject(posX, posY, float64(config.BlockSize), float64(config.BlockSize))
+	dresolv.SetObject(exit, obj)
+	dresolv.SetCollider(exit, components.LayerTrigger, 0)
+	components.Trigger.SetValue(exit, components.TriggerData{Kind: components.TriggerExit, Mask: components.LayerPlayer})
+
+	return exit
+}
@@ -0,0 +1,22 @@
+package factory
+
+import (
+	"github.com/AndriiPets/FishGame/archetypes"
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	"github.com/yohamta/donburi/features/math"
+)
+
+func CreateNoise(ecs *ecs.ECS, pos math.Vec2, radius float64, tick int) *donburi.Entry {
+	noise := archetypes.Noise.Spawn(ecs)
+
+	components.Noise.SetValue(noise, components.NoiseData{
+		Position:  pos,
+		Radius:    radius,
+		SpawnTick: tick,
+		Duration:  0.5,
+	})
+
+	return noise
+}
@@ -15,16 +15,23 @@ import (
 type ParticleType string
 
 const (
-	ParticleDust     ParticleType = "dust"
-	ParticleGunFlash ParticleType = "gun_flash"
+	ParticleDust      ParticleType = "dust"
+	ParticleGunFlash  ParticleType = "gun_flash"
+	ParticleDebris    ParticleType = "debris"
+	ParticleExplosion ParticleType = "explosion"
 )
 
 func CreateParticle(ecs *ecs.ECS, posX, posY float64, pType ParticleType, rotation float64, flipH, flipV bool) *donburi.Entry {
+	return CreateEffect(ecs, posX, posY, "particle_"+string(pType), rotation, flipH, flipV)
+}
+
+// CreateEffect spawns a one shot particle playing any animation by name.
+func CreateEffect(ecs *ecs.ECS, posX, posY float64, animName string, rotation float64, flipH, flipV bool) *donburi.Entry {
 	particle := archetypes.ParticleSprite.Spawn(ecs)
 
 	//set animation
 	animation := components.Animation.Get(particle)
-	anim := assets.GetAnimation("particle_" + string(pType))
+	anim := assets.GetAnimation(animName)
 	anim.SetOnLoop(ganim8.PauseAtEnd)
 
 	animation.Animation = anim
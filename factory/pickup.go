@@ -0,0 +1,36 @@
+package factory
+
+import (
+	"github.com/AndriiPets/FishGame/archetypes"
+	"github.com/AndriiPets/FishGame/assets"
+	"github.com/AndriiPets/FishGame/components"
+	dresolv "github.com/AndriiPets/FishGame/resolv"
+	"github.com/AndriiPets/FishGame/resources"
+
+	"github.com/solarlune/resolv"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
+
+// CreateWeaponPickup places a weapon on the floor, it can be collected once
+// delay seconds have passed since tick.
+func CreateWeaponPickup(ecs *ecs.ECS, posX, posY float64, weapon components.WeaponSlot, tick int, delay float64) *donburi.Entry {
+	pickup := archetypes.WeaponPickup.Spawn(ecs)
+
+	components.Pickup.SetValue(pickup, components.PickupData{
+		Weapon:    weapon,
+		SpawnTick: tick,
+		Delay:     delay,
+	})
+
+	//setup animation
+	animation := components.Animation.Get(pickup)
+	animation.Animation = assets.GetAnimation(resources.WeaponMap[weapon.Type].Sprite)
+
+	obj := resolv.NewObject(posX, posY, 16, 16)
+	dresolv.SetObject(pickup, obj)
+	dresolv.SetCollider(pickup, components.LayerPickup, 0)
+	components.Trigger.SetValue(pickup, components.TriggerData{Kind: components.TriggerPickup, Mask: components.LayerPlayer})
+
+	return pickup
+}
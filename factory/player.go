@@ -4,12 +4,14 @@ import (
 	"github.com/AndriiPets/FishGame/archetypes"
 	"github.com/AndriiPets/FishGame/components"
 	dresolv "github.com/AndriiPets/FishGame/resolv"
+	"github.com/AndriiPets/FishGame/resources"
 	"github.com/tanema/gween"
 	"github.com/tanema/gween/ease"
 
 	"github.com/solarlune/resolv"
 	"github.com/yohamta/donburi"
 	"github.com/yohamta/donburi/ecs"
+	"github.com/yohamta/donburi/features/hierarchy"
 	"github.com/yohamta/donburi/features/math"
 )
 
@@ -27,22 +29,30 @@ func CreatePlayer(ecs *ecs.ECS, posX, posY float64) *donburi.Entry {
 	animation.Type = components.AnimationActor
 
 	obj := resolv.NewObject(posX, posY, 16, 16)
-	//obj.AddTags("damageble")
 	dresolv.SetObject(player, obj)
+	dresolv.SetCollider(player, components.LayerPlayer, components.LayerWall|components.LayerProp|components.LayerEnemyProjectile|components.LayerPlayerProjectile)
 	components.Player.SetValue(player, components.PlayerData{
 		FacingRight: true,
 		IsDashing:   false,
 	})
 	components.Shooter.SetValue(player, components.ShooterData{
-		Type:      "default", //bouncer, //default
 		Fire:      false,
 		CanFire:   true,
 		HoldRange: 15,
 	})
+
+	//the player starts with the first weapon available to them
+	start := components.NewWeaponSlot(resources.PlayerWeapons[0])
+	components.Inventory.SetValue(player, components.InventoryData{
+		Slots:    []components.WeaponSlot{start},
+		Capacity: 4,
+	})
+	components.Shooter.Get(player).SetWeapon(start.Type)
 	components.Velocity.SetValue(player, components.VelocityData{
 		Vel: math.NewVec2(0, 0),
 	})
 	components.Health.SetValue(player, components.HealthData{
+		Ammount:  5,
 		Cooldown: 0.2,
 	})
 
@@ -55,6 +65,7 @@ func CreatePlayer(ecs *ecs.ECS, posX, posY float64) *donburi.Entry {
 
 	wSprite.SetComponent(components.Shooter, player.Component(components.Shooter))
 	wSprite.SetComponent(components.AttackVector, player.Component(components.AttackVector))
+	hierarchy.SetParent(wSprite, player)
 
 	dresolv.SetObject(wSprite, resolv.NewObject(posX, posY, 16, 16))
 	wAnimation.Animation = shooter.Animation()
@@ -0,0 +1,22 @@
+package factory
+
+import (
+	"github.com/AndriiPets/FishGame/archetypes"
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	"github.com/yohamta/donburi/features/math"
+)
+
+func CreateTracer(ecs *ecs.ECS, from, to math.Vec2, tick int) *donburi.Entry {
+	tracer := archetypes.Tracer.Spawn(ecs)
+
+	components.Tracer.SetValue(tracer, components.TracerData{
+		From:      from,
+		To:        to,
+		SpawnTick: tick,
+		Duration:  0.08,
+	})
+
+	return tracer
+}
@@ -0,0 +1,56 @@
+package factory
+
+import (
+	"github.com/AndriiPets/FishGame/archetypes"
+	"github.com/AndriiPets/FishGame/components"
+	dresolv "github.com/AndriiPets/FishGame/resolv"
+
+	"github.com/solarlune/resolv"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
+
+// damage a spike trap deals to everything stepping on it
+const trapDamage = 1
+
+// CreateTrigger places a trigger volume, it goes off for entries on the layers
+// of the trigger's mask.
+func CreateTrigger(ecs *ecs.ECS, posX, posY, w, h float64, trigger components.TriggerData) *donburi.Entry {
+	e := archetypes.Trigger.Spawn(ecs)
+
+	dresolv.SetObject(e, resolv.NewObject(posX, posY, w, h))
+	dresolv.SetCollider(e, components.LayerTrigger, 0)
+	components.Trigger.SetValue(e, trigger)
+
+	return e
+}
+
+// CreateTrap places spikes that hurt any actor stepping on them.
+func CreateTrap(ecs *ecs.ECS, posX, posY, w, h float64) *donburi.Entry {
+	return CreateTrigger(ecs, posX, posY, w, h, components.TriggerData{
+		Kind:   components.TriggerTrap,
+		Mask:   components.LayerPlayer | components.LayerEnemy,
+		Damage: trapDamage,
+	})
+}
+
+// CreatePrompt places an area that shows the text while the player is in it.
+func CreatePrompt(ecs *ecs.ECS, posX, posY, w, h float64, text string) *donburi.Entry {
+	return CreateTrigger(ecs, posX, posY, w, h, components.TriggerData{
+		Kind: components.TriggerPrompt,
+		Mask: components.LayerPlayer,
+		Text: text,
+	})
+}
+
+// CreateRoom places the trigger of a room, the doors are closed while the
+// player fights the enemies inside.
+func CreateRoom(ecs *ecs.ECS, posX, posY, w, h float64) *donburi.Entry {
+	room := archetypes.Room.Spawn(ecs)
+
+	dresolv.SetObject(room, resolv.NewObject(posX, posY, w, h))
+	dresolv.SetCollider(room, components.LayerTrigger, 0)
+	components.Trigger.SetValue(room, components.TriggerData{Kind: components.TriggerRoom, Mask: components.LayerPlayer})
+
+	return room
+}
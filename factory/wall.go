@@ -3,6 +3,7 @@ package factory
 import (
 	"github.com/AndriiPets/FishGame/archetypes"
 	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/config"
 	dresolv "github.com/AndriiPets/FishGame/resolv"
 
 	"github.com/solarlune/resolv"
@@ -19,7 +20,6 @@ func CreateWall(ecs *ecs.ECS, obj *resolv.Object, blockType components.BlockType
 	switch blockType {
 	case components.BlockWall:
 		block.Type = components.BlockWall
-		obj.AddTags("solid")
 	case components.BlockFloor:
 		block.Type = components.BlockFloor
 	default:
@@ -32,6 +32,43 @@ func CreateWall(ecs *ecs.ECS, obj *resolv.Object, blockType components.BlockType
 	animation.Type = components.AnimationStatic
 
 	dresolv.SetObject(wall, obj)
+	if block.Type == components.BlockWall {
+		dresolv.SetCollider(wall, components.LayerWall, 0)
+	}
 
 	return wall
 }
+
+const destructibleWallHealth = 3
+
+// tint setting destructible walls apart from the solid ones
+var destructibleTint = [3]float64{0.85, 0.75, 0.65}
+
+// CreateDestructibleWall is a wall that is worn down by bullets until it
+// crumbles.
+func CreateDestructibleWall(ecs *ecs.ECS, obj *resolv.Object) *donburi.Entry {
+	wall := CreateWall(ecs, obj, components.BlockWall)
+	components.Block.Get(wall).Destructable = true
+
+	wall.AddComponent(components.Health)
+	components.Health.SetValue(wall, components.HealthData{Ammount: destructibleWallHealth})
+	wall.AddComponent(components.Despawnable)
+	components.Animation.Get(wall).Tint = destructibleTint
+
+	return wall
+}
+
+// color of the walls closing a locked room
+var doorTint = [3]float64{1, 0.55, 0.45}
+
+// CreateDoor closes a doorway while a room is locked, it is a wall that can be
+// despawned again.
+func CreateDoor(ecs *ecs.ECS, posX, posY float64) *donburi.Entry {
+	size := float64(config.BlockSize)
+	door := CreateWall(ecs, resolv.NewObject(posX, posY, size, size), components.BlockWall)
+
+	door.AddComponent(components.Despawnable)
+	components.Animation.Get(door).Tint = doorTint
+
+	return door
+}
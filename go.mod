@@ -2,16 +2,19 @@ module github.com/AndriiPets/FishGame
 
 go 1.21.6
 
+require (
+	github.com/hajimehoshi/ebiten/v2 v2.6.6
+	github.com/quasilyte/pathing v0.0.0-20231012081721-0370212e864a
+	github.com/solarlune/resolv v0.7.0
+	github.com/tanema/gween v0.0.0-20221212145351-621cc8a459d1
+	github.com/yohamta/donburi v1.3.13
+	github.com/yohamta/ganim8/v2 v2.1.29
+)
+
 require (
 	github.com/ebitengine/purego v0.6.0 // indirect
-	github.com/hajimehoshi/ebiten/v2 v2.6.6 // indirect
 	github.com/jezek/xgb v1.1.0 // indirect
-	github.com/quasilyte/pathing v0.0.0-20231012081721-0370212e864a // indirect
 	github.com/solarlune/dngn v0.0.0-20230827152346-e9a1e2a5a868 // indirect
-	github.com/solarlune/resolv v0.7.0 // indirect
-	github.com/tanema/gween v0.0.0-20221212145351-621cc8a459d1 // indirect
-	github.com/yohamta/donburi v1.3.13 // indirect
-	github.com/yohamta/ganim8/v2 v2.1.29 // indirect
 	golang.org/x/exp v0.0.0-20221126150942-6ab00d035af9 // indirect
 	golang.org/x/exp/shiny v0.0.0-20230817173708-d852ddb80c63 // indirect
 	golang.org/x/image v0.12.0 // indirect
@@ -0,0 +1,198 @@
+package input
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type Action string
+
+const (
+	ActionMove       Action = "move"
+	ActionAim        Action = "aim"
+	ActionFire       Action = "fire"
+	ActionDash       Action = "dash"
+	ActionSwapWeapon Action = "swap_weapon"
+	ActionReload     Action = "reload"
+	ActionPrevWeapon Action = "prev_weapon"
+	ActionDrop       Action = "drop_weapon"
+	ActionSlots      Action = "weapon_slots"
+	ActionPause      Action = "pause"
+)
+
+type Stick string
+
+const (
+	StickNone  Stick = ""
+	StickLeft  Stick = "left"
+	StickRight Stick = "right"
+)
+
+// Button is a digital action that is active when any of its inputs is pressed.
+type Button struct {
+	Keys    []ebiten.Key    `json:"keys"`
+	Mouse   []MouseButton   `json:"mouse"`
+	Gamepad []GamepadButton `json:"gamepad"`
+}
+
+// Axis is an analog action made of four digital directions and an optional stick.
+type Axis struct {
+	Up    Button `json:"up"`
+	Down  Button `json:"down"`
+	Left  Button `json:"left"`
+	Right Button `json:"right"`
+	Stick Stick  `json:"stick"`
+}
+
+type Aim struct {
+	Mouse bool  `json:"mouse"`
+	Stick Stick `json:"stick"`
+}
+
+// Menu holds the buttons that move through menus.
+type Menu struct {
+	Up      Button `json:"up"`
+	Down    Button `json:"down"`
+	Confirm Button `json:"confirm"`
+	Back    Button `json:"back"`
+}
+
+type Bindings struct {
+	Deadzone   float64 `json:"deadzone"`
+	Move       Axis    `json:"move"`
+	Aim        Aim     `json:"aim"`
+	Fire       Button  `json:"fire"`
+	Dash       Button  `json:"dash"`
+	SwapWeapon Button  `json:"swap_weapon"` //next weapon
+	PrevWeapon Button  `json:"prev_weapon"`
+	Reload     Button  `json:"reload"`
+	Drop       Button  `json:"drop_weapon"`
+	Pause      Button  `json:"pause"`
+	Menu       Menu    `json:"menu"`
+
+	WeaponSlots []Button `json:"weapon_slots"` //select an inventory slot directly, in slot order
+	WheelCycle  bool     `json:"wheel_cycle"`  //mouse wheel cycles weapons
+}
+
+//go:embed bindings.json
+var defaultBindings []byte
+
+// DefaultBindings returns the bindings shipped with the game.
+func DefaultBindings() *Bindings {
+	b, err := ParseBindings(defaultBindings)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+// LoadBindings reads bindings from a JSON file, an empty path yields the defaults.
+func LoadBindings(path string) (*Bindings, error) {
+	if path == "" {
+		return DefaultBindings(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := ParseBindings(data)
+	if err != nil {
+		return nil, fmt.Errorf("bindings %s: %w", path, err)
+	}
+
+	return b, nil
+}
+
+func ParseBindings(data []byte) (*Bindings, error) {
+	b := &Bindings{}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, err
+	}
+
+	for _, s := range []Stick{b.Move.Stick, b.Aim.Stick} {
+		if s != StickNone && s != StickLeft && s != StickRight {
+			return nil, fmt.Errorf("unknown stick: %q", s)
+		}
+	}
+
+	//files without menu buttons keep the default ones, menus can't be left
+	//without them
+	defaults := &Bindings{}
+	if err := json.Unmarshal(defaultBindings, defaults); err != nil {
+		return nil, err
+	}
+	for _, pair := range []struct{ btn, def *Button }{
+		{&b.Menu.Up, &defaults.Menu.Up},
+		{&b.Menu.Down, &defaults.Menu.Down},
+		{&b.Menu.Confirm, &defaults.Menu.Confirm},
+		{&b.Menu.Back, &defaults.Menu.Back},
+	} {
+		if pair.btn.unbound() {
+			*pair.btn = *pair.def
+		}
+	}
+
+	return b, nil
+}
+
+func (b Button) unbound() bool {
+	return len(b.Keys) == 0 && len(b.Mouse) == 0 && len(b.Gamepad) == 0
+}
+
+type MouseButton ebiten.MouseButton
+
+var mouseButtonNames = map[string]ebiten.MouseButton{
+	"left":   ebiten.MouseButtonLeft,
+	"right":  ebiten.MouseButtonRight,
+	"middle": ebiten.MouseButtonMiddle,
+}
+
+func (m *MouseButton) UnmarshalText(text []byte) error {
+	b, ok := mouseButtonNames[string(text)]
+	if !ok {
+		return fmt.Errorf("unknown mouse button: %q", text)
+	}
+
+	*m = MouseButton(b)
+	return nil
+}
+
+// GamepadButton is a button of the standard gamepad layout.
+type GamepadButton ebiten.StandardGamepadButton
+
+var gamepadButtonNames = map[string]ebiten.StandardGamepadButton{
+	"RightBottom":      ebiten.StandardGamepadButtonRightBottom,
+	"RightRight":       ebiten.StandardGamepadButtonRightRight,
+	"RightLeft":        ebiten.StandardGamepadButtonRightLeft,
+	"RightTop":         ebiten.StandardGamepadButtonRightTop,
+	"FrontTopLeft":     ebiten.StandardGamepadButtonFrontTopLeft,
+	"FrontTopRight":    ebiten.StandardGamepadButtonFrontTopRight,
+	"FrontBottomLeft":  ebiten.StandardGamepadButtonFrontBottomLeft,
+	"FrontBottomRight": ebiten.StandardGamepadButtonFrontBottomRight,
+	"CenterLeft":       ebiten.StandardGamepadButtonCenterLeft,
+	"CenterRight":      ebiten.StandardGamepadButtonCenterRight,
+	"LeftStick":        ebiten.StandardGamepadButtonLeftStick,
+	"RightStick":       ebiten.StandardGamepadButtonRightStick,
+	"LeftTop":          ebiten.StandardGamepadButtonLeftTop,
+	"LeftBottom":       ebiten.StandardGamepadButtonLeftBottom,
+	"LeftLeft":         ebiten.StandardGamepadButtonLeftLeft,
+	"LeftRight":        ebiten.StandardGamepadButtonLeftRight,
+	"CenterCenter":     ebiten.StandardGamepadButtonCenterCenter,
+}
+
+func (g *GamepadButton) UnmarshalText(text []byte) error {
+	b, ok := gamepadButtonNames[string(text)]
+	if !ok {
+		return fmt.Errorf("unknown gamepad button: %q", text)
+	}
+
+	*g = GamepadButton(b)
+	return nil
+}
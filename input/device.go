@@ -0,0 +1,162 @@
+package input
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/donburi/features/math"
+)
+
+// Device is a Source that evaluates bindings against the keyboard, mouse and
+// the first connected gamepad with a standard layout.
+type Device struct {
+	Bindings *Bindings
+
+	gamepads   []ebiten.GamepadID
+	lastCursor math.Vec2
+	stickAim   math.Vec2
+	usingStick bool
+}
+
+func NewDevice(b *Bindings) *Device {
+	return &Device{Bindings: b}
+}
+
+func (d *Device) Poll(tick int) State {
+	b := d.Bindings
+	pad, hasPad := d.gamepad()
+
+	s := State{
+		Fire:       d.pressed(b.Fire, pad, hasPad),
+		Dash:       d.pressed(b.Dash, pad, hasPad),
+		SwapWeapon: d.pressed(b.SwapWeapon, pad, hasPad),
+		PrevWeapon: d.pressed(b.PrevWeapon, pad, hasPad),
+		Reload:     d.pressed(b.Reload, pad, hasPad),
+		Drop:       d.pressed(b.Drop, pad, hasPad),
+		Pause:      d.pressed(b.Pause, pad, hasPad),
+
+		MenuUp:      d.pressed(b.Menu.Up, pad, hasPad),
+		MenuDown:    d.pressed(b.Menu.Down, pad, hasPad),
+		MenuConfirm: d.pressed(b.Menu.Confirm, pad, hasPad),
+		MenuBack:    d.pressed(b.Menu.Back, pad, hasPad),
+	}
+
+	for i, slot := range b.WeaponSlots {
+		if d.pressed(slot, pad, hasPad) {
+			s.Slot = i + 1
+			break
+		}
+	}
+
+	//scrolling down moves to the next weapon
+	if b.WheelCycle {
+		if _, dy := ebiten.Wheel(); dy < 0 {
+			s.Scroll = 1
+		} else if dy > 0 {
+			s.Scroll = -1
+		}
+	}
+
+	//digital movement
+	if d.pressed(b.Move.Up, pad, hasPad) {
+		s.Move.Y -= 1
+	}
+	if d.pressed(b.Move.Down, pad, hasPad) {
+		s.Move.Y += 1
+	}
+	if d.pressed(b.Move.Left, pad, hasPad) {
+		s.Move.X -= 1
+	}
+	if d.pressed(b.Move.Right, pad, hasPad) {
+		s.Move.X += 1
+	}
+
+	//analog movement overrides the digital one while the stick is pushed
+	if hasPad {
+		if stick := d.stick(b.Move.Stick, pad); !stick.IsZero() {
+			s.Move = stick
+		}
+	}
+
+	//aiming, the stick wins until the mouse is moved again
+	if b.Aim.Mouse {
+		cx, cy := ebiten.CursorPosition()
+		s.Cursor = math.NewVec2(float64(cx), float64(cy))
+
+		if !s.Cursor.Equal(d.lastCursor) {
+			d.usingStick = false
+		}
+		d.lastCursor = s.Cursor
+	}
+
+	if hasPad {
+		if stick := d.stick(b.Aim.Stick, pad); !stick.IsZero() {
+			d.stickAim = stick
+			d.usingStick = true
+		}
+	}
+
+	if d.usingStick || !b.Aim.Mouse {
+		s.Aim = d.stickAim
+	}
+
+	return s
+}
+
+func (d *Device) gamepad() (ebiten.GamepadID, bool) {
+	d.gamepads = ebiten.AppendGamepadIDs(d.gamepads[:0])
+	for _, id := range d.gamepads {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
+
+func (d *Device) pressed(btn Button, pad ebiten.GamepadID, hasPad bool) bool {
+	for _, k := range btn.Keys {
+		if ebiten.IsKeyPressed(k) {
+			return true
+		}
+	}
+
+	for _, m := range btn.Mouse {
+		if ebiten.IsMouseButtonPressed(ebiten.MouseButton(m)) {
+			return true
+		}
+	}
+
+	if hasPad {
+		for _, g := range btn.Gamepad {
+			if ebiten.IsStandardGamepadButtonPressed(pad, ebiten.StandardGamepadButton(g)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// stick returns the stick vector or zero while it rests inside the deadzone.
+func (d *Device) stick(s Stick, pad ebiten.GamepadID) math.Vec2 {
+	var h, v ebiten.StandardGamepadAxis
+
+	switch s {
+	case StickLeft:
+		h, v = ebiten.StandardGamepadAxisLeftStickHorizontal, ebiten.StandardGamepadAxisLeftStickVertical
+	case StickRight:
+		h, v = ebiten.StandardGamepadAxisRightStickHorizontal, ebiten.StandardGamepadAxisRightStickVertical
+	default:
+		return math.Vec2{}
+	}
+
+	vec := math.NewVec2(
+		ebiten.StandardGamepadAxisValue(pad, h),
+		ebiten.StandardGamepadAxisValue(pad, v),
+	)
+
+	if vec.Magnitude() < d.Bindings.Deadzone {
+		return math.Vec2{}
+	}
+
+	return vec
+}
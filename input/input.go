@@ -0,0 +1,59 @@
+package input
+
+import (
+	"github.com/yohamta/donburi/features/math"
+)
+
+// State is the input snapshot consumed by the gameplay systems for a single tick.
+type State struct {
+	Move       math.Vec2 //movement direction, not normalized
+	Aim        math.Vec2 //aim direction, overrides Cursor when not zero
+	Cursor     math.Vec2 //cursor position on the screen
+	Fire       bool
+	Dash       bool
+	SwapWeapon bool //next weapon
+	PrevWeapon bool
+	Reload     bool
+	Drop       bool
+	Slot       int //inventory slot selected directly, 1 based, 0 when none
+	Scroll     int //weapon cycling from the mouse wheel, -1, 0 or 1
+	Pause      bool
+
+	//menu navigation, menus read it from the same source as the game so
+	//replays go through them too
+	MenuUp      bool
+	MenuDown    bool
+	MenuConfirm bool
+	MenuBack    bool
+}
+
+// Source produces the input state for the given simulation tick.
+type Source interface {
+	Poll(tick int) State
+}
+
+// Script is a Source backed by a plain function, handy for headless runs and tests.
+type Script func(tick int) State
+
+func (s Script) Poll(tick int) State {
+	return s(tick)
+}
+
+// Idle is a Source that never presses anything.
+var Idle = Script(func(tick int) State {
+	return State{}
+})
+
+// Sequence polls its source with the number of polls made so far instead of
+// the caller's tick. Menus run while the game clock stands still, wrapping
+// the source keeps menus and levels on one timeline for replays.
+type Sequence struct {
+	Source Source
+	polls  int
+}
+
+func (s *Sequence) Poll(tick int) State {
+	state := s.Source.Poll(s.polls)
+	s.polls++
+	return state
+}
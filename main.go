@@ -2,30 +2,35 @@ package main
 
 import (
 	//"fmt"
+	"flag"
 	"image"
 	"log"
-
-	//"time"
+	"time"
 
 	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/input"
+	"github.com/AndriiPets/FishGame/replay"
 	"github.com/AndriiPets/FishGame/scenes"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
-type Scene interface {
-	Update()
-	Draw(screen *ebiten.Image)
-}
-
 type Game struct {
 	bounds image.Rectangle
-	scene  Scene
+	scenes *scenes.Manager
 }
 
-func NewGame() *Game {
+// NewGame opens on the title menu, or drops straight into a level when
+// skipTitle is set.
+func NewGame(source input.Source, seed int64, skipTitle bool) *Game {
 	g := &Game{
 		bounds: image.Rectangle{},
-		scene:  &scenes.MainScene{},
+		scenes: scenes.NewManager(),
+	}
+
+	if skipTitle {
+		g.scenes.Push(scenes.NewMainScene(g.scenes, source, seed))
+	} else {
+		g.scenes.Push(scenes.NewTitleScene(g.scenes, source, seed))
 	}
 
 	//go func() {
@@ -44,13 +49,12 @@ func NewGame() *Game {
 }
 
 func (g *Game) Update() error {
-	g.scene.Update()
-	return nil
+	return g.scenes.Update()
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
 	screen.Clear()
-	g.scene.Draw(screen)
+	g.scenes.Draw(screen)
 }
 
 func (g *Game) Layout(width, height int) (int, int) {
@@ -59,10 +63,52 @@ func (g *Game) Layout(width, height int) (int, int) {
 }
 
 func main() {
+	bindingsPath := flag.String("bindings", "", "path to a JSON input bindings file")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed for the level and gameplay randomness")
+	replayPath := flag.String("replay", "", "play back a recorded replay file")
+	recordPath := flag.String("record", "", "record the session input into a replay file")
+	skipTitle := flag.Bool("play", false, "skip the title menu and start playing right away")
+	flag.StringVar(&config.C.Level, "level", config.C.Level, "tiled level to play, embedded level name or path to a .json file")
+	flag.StringVar(&config.C.WeaponsFile, "weapons", config.C.WeaponsFile, "json file with weapon definitions merged over the embedded ones")
+	flag.Parse()
+
+	if err := scenes.CheckLevel(); err != nil {
+		log.Fatal(err)
+	}
+
+	bindings, err := input.LoadBindings(*bindingsPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var source input.Source = input.NewDevice(bindings)
+
+	if *replayPath != "" {
+		player, err := replay.Open(*replayPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		*seed = player.Header.Seed
+		*skipTitle = !player.Header.Title
+		source = player
+	}
+
+	if *recordPath != "" {
+		recorder, err := replay.Create(*recordPath, replay.Header{Seed: *seed, Title: !*skipTitle}, source)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer recorder.Close()
+		source = recorder
+	}
+
+	//menus poll the source while the game clock stands still
+	source = &input.Sequence{Source: source}
+
 	ebiten.SetWindowSize(config.C.ScreenWidth, config.C.ScreenHeight)
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeDisabled)
 	ebiten.SetCursorMode(ebiten.CursorModeHidden)
-	if err := ebiten.RunGame(NewGame()); err != nil {
-		log.Fatal(err)
+	if err := ebiten.RunGame(NewGame(source, *seed, *skipTitle)); err != nil {
+		log.Print(err)
 	}
 }
@@ -0,0 +1,250 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/AndriiPets/FishGame/input"
+	"github.com/yohamta/donburi/features/math"
+)
+
+// Version of the replay format written by Recorder.
+const Version = 2
+
+// A replay file is JSON lines: a Header followed by Frames. A frame is only
+// written when the input changes, the state holds until the next frame.
+
+type Header struct {
+	Version int   `json:"version"`
+	Seed    int64 `json:"seed"`
+	Title   bool  `json:"title,omitempty"` //the session started on the title menu
+}
+
+type Frame struct {
+	Tick   int       `json:"t"`
+	Move   []float64 `json:"m,omitempty"`
+	Aim    []float64 `json:"a,omitempty"`
+	Cursor []float64 `json:"c,omitempty"`
+	Fire   bool      `json:"f,omitempty"`
+	Dash   bool      `json:"d,omitempty"`
+	Swap   bool      `json:"s,omitempty"`
+	Reload bool      `json:"r,omitempty"`
+	Prev   bool      `json:"p,omitempty"`
+	Drop   bool      `json:"x,omitempty"`
+	Slot   int       `json:"n,omitempty"`
+	Scroll int       `json:"w,omitempty"`
+	Pause  bool      `json:"e,omitempty"`
+	Menu   []bool    `json:"u,omitempty"` //up, down, confirm and back
+	End    bool      `json:"end,omitempty"`
+}
+
+func newFrame(tick int, s input.State) Frame {
+	return Frame{
+		Tick:   tick,
+		Move:   vecToSlice(s.Move),
+		Aim:    vecToSlice(s.Aim),
+		Cursor: vecToSlice(s.Cursor),
+		Fire:   s.Fire,
+		Dash:   s.Dash,
+		Swap:   s.SwapWeapon,
+		Reload: s.Reload,
+		Prev:   s.PrevWeapon,
+		Drop:   s.Drop,
+		Slot:   s.Slot,
+		Scroll: s.Scroll,
+		Pause:  s.Pause,
+		Menu:   menuToSlice(s),
+	}
+}
+
+func (f Frame) State() input.State {
+	s := input.State{
+		Move:       sliceToVec(f.Move),
+		Aim:        sliceToVec(f.Aim),
+		Cursor:     sliceToVec(f.Cursor),
+		Fire:       f.Fire,
+		Dash:       f.Dash,
+		SwapWeapon: f.Swap,
+		Reload:     f.Reload,
+		PrevWeapon: f.Prev,
+		Drop:       f.Drop,
+		Slot:       f.Slot,
+		Scroll:     f.Scroll,
+		Pause:      f.Pause,
+	}
+	if len(f.Menu) == 4 {
+		s.MenuUp, s.MenuDown, s.MenuConfirm, s.MenuBack = f.Menu[0], f.Menu[1], f.Menu[2], f.Menu[3]
+	}
+
+	return s
+}
+
+func vecToSlice(v math.Vec2) []float64 {
+	if v.IsZero() {
+		return nil
+	}
+	return []float64{v.X, v.Y}
+}
+
+func menuToSlice(s input.State) []bool {
+	if !s.MenuUp && !s.MenuDown && !s.MenuConfirm && !s.MenuBack {
+		return nil
+	}
+	return []bool{s.MenuUp, s.MenuDown, s.MenuConfirm, s.MenuBack}
+}
+
+func sliceToVec(s []float64) math.Vec2 {
+	if len(s) != 2 {
+		return math.Vec2{}
+	}
+	return math.NewVec2(s[0], s[1])
+}
+
+// Recorder is a Source that passes input through while writing it to a replay.
+type Recorder struct {
+	source  input.Source
+	w       io.WriteCloser
+	enc     *json.Encoder
+	last    input.State
+	tick    int
+	started bool
+}
+
+func NewRecorder(w io.WriteCloser, header Header, source input.Source) (*Recorder, error) {
+	r := &Recorder{
+		source: source,
+		w:      w,
+		enc:    json.NewEncoder(w),
+	}
+
+	header.Version = Version
+	if err := r.enc.Encode(header); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Create starts recording into a new file.
+func Create(path string, header Header, source input.Source) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRecorder(f, header, source)
+}
+
+func (r *Recorder) Poll(tick int) input.State {
+	s := r.source.Poll(tick)
+	r.tick = tick
+
+	//only what a frame stores decides whether a new one is written
+	frame := newFrame(tick, s)
+	recorded := frame.State()
+
+	if !r.started || recorded != r.last {
+		//a failed write should not take the game down, the replay is just cut short
+		if err := r.enc.Encode(frame); err != nil {
+			fmt.Fprintln(os.Stderr, "replay:", err)
+		}
+		r.last = recorded
+		r.started = true
+	}
+
+	return s
+}
+
+// Close marks the last recorded tick and closes the writer.
+func (r *Recorder) Close() error {
+	err := r.enc.Encode(Frame{Tick: r.tick, End: true})
+	if cerr := r.w.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Player is a Source that plays back a recorded replay.
+type Player struct {
+	Header Header
+
+	frames  []Frame
+	next    int
+	current input.State
+	end     int
+}
+
+func Read(rd io.Reader) (*Player, error) {
+	dec := json.NewDecoder(rd)
+	p := &Player{end: -1}
+
+	if err := dec.Decode(&p.Header); err != nil {
+		return nil, fmt.Errorf("replay header: %w", err)
+	}
+	if p.Header.Version != Version {
+		return nil, fmt.Errorf("unsupported replay version %d, expected %d", p.Header.Version, Version)
+	}
+
+	for {
+		var f Frame
+		err := dec.Decode(&f)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("replay frame %d: %w", len(p.frames), err)
+		}
+
+		if f.End {
+			p.end = f.Tick
+			break
+		}
+		p.frames = append(p.frames, f)
+	}
+
+	return p, nil
+}
+
+// Open loads a replay file.
+func Open(path string) (*Player, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+func (p *Player) Poll(tick int) input.State {
+	if p.Done(tick) {
+		return input.State{}
+	}
+
+	for p.next < len(p.frames) && p.frames[p.next].Tick <= tick {
+		p.current = p.frames[p.next].State()
+		p.next++
+	}
+
+	return p.current
+}
+
+// Done reports whether the replay has no more input past the given tick.
+func (p *Player) Done(tick int) bool {
+	return tick > p.Ticks()
+}
+
+// Ticks returns the number of recorded ticks. Replays cut short without an
+// end marker finish after their last frame.
+func (p *Player) Ticks() int {
+	if p.end >= 0 {
+		return p.end
+	}
+	if len(p.frames) == 0 {
+		return 0
+	}
+	return p.frames[len(p.frames)-1].Tick
+}
@@ -0,0 +1,168 @@
+package replay_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/input"
+	"github.com/AndriiPets/FishGame/replay"
+	"github.com/AndriiPets/FishGame/scenes"
+	"github.com/AndriiPets/FishGame/systems"
+	"github.com/AndriiPets/FishGame/tags"
+	"github.com/solarlune/resolv"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/features/math"
+)
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// strafe walks in a square while shooting to the right.
+var strafe = input.Script(func(tick int) input.State {
+	dirs := []math.Vec2{
+		math.NewVec2(1, 0),
+		math.NewVec2(0, 1),
+		math.NewVec2(-1, 0),
+		math.NewVec2(0, -1),
+	}
+
+	return input.State{
+		Move: dirs[(tick/60)%len(dirs)],
+		Aim:  math.NewVec2(1, 0),
+		Fire: tick%2 == 0,
+	}
+})
+
+// endState is what the recorded and the replayed run are compared on.
+type endState struct {
+	Tick    int
+	Player  resolv.Vector
+	Health  int
+	Dead    bool
+	Enemies []resolv.Vector
+	Summary scenes.Summary
+}
+
+func snapshot(ms *scenes.MainScene) endState {
+	world := ms.ECS().World
+	player := components.Player.MustFirst(world)
+	health := components.Health.Get(player)
+
+	state := endState{
+		Tick:    systems.GetClock(ms.ECS()).Tick,
+		Player:  components.Object.Get(player).Position,
+		Health:  health.Ammount,
+		Dead:    health.Dead,
+		Summary: ms.Summary(),
+	}
+	tags.Enemy.Each(world, func(e *donburi.Entry) {
+		state.Enemies = append(state.Enemies, components.Object.Get(e).Position)
+	})
+
+	return state
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		source input.Source
+		seed   int64
+		ticks  int
+	}{
+		{"idle", input.Idle, 1, 120},
+		{"strafe", strafe, 1, 300},
+		//the player dies and the run restarts on a new seed
+		{"strafe past a restart", strafe, 1, 900},
+		{"other seed", strafe, 7, 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			rec, err := replay.NewRecorder(nopCloser{&buf}, replay.Header{Seed: tt.seed}, tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			recorded := scenes.NewHeadlessScene(rec, tt.seed)
+			recorded.Run(tt.ticks)
+			if err := rec.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			player, err := replay.Read(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if player.Header.Seed != tt.seed {
+				t.Fatalf("header seed %d, want %d", player.Header.Seed, tt.seed)
+			}
+
+			replayed := scenes.NewHeadlessScene(input.Script(player.Poll), player.Header.Seed)
+			replayed.Run(player.Ticks())
+
+			a, b := snapshot(recorded), snapshot(replayed)
+			if a.Tick != b.Tick || a.Player != b.Player || a.Health != b.Health || a.Dead != b.Dead || a.Summary != b.Summary {
+				t.Fatalf("replay differs:\n%+v\n%+v", a, b)
+			}
+			if len(a.Enemies) != len(b.Enemies) {
+				t.Fatalf("%d enemies, replayed %d", len(a.Enemies), len(b.Enemies))
+			}
+			for i := range a.Enemies {
+				if a.Enemies[i] != b.Enemies[i] {
+					t.Fatalf("enemy %d at %v, replayed at %v", i, a.Enemies[i], b.Enemies[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecorderFrames(t *testing.T) {
+	right := math.NewVec2(1, 0)
+
+	tests := []struct {
+		name   string
+		states []input.State
+		frames int
+	}{
+		{"first tick is always written", []input.State{{}}, 1},
+		{"held input is one frame", []input.State{{Fire: true}, {Fire: true}, {Fire: true}}, 1},
+		{"every change is a frame", []input.State{{}, {Move: right}, {Move: right, Fire: true}, {}}, 4},
+		{"pause is recorded", []input.State{{}, {Pause: true}, {}}, 3},
+		{"menus are recorded", []input.State{{MenuDown: true}, {}, {MenuConfirm: true}, {MenuBack: true}, {MenuUp: true}}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			rec, err := replay.NewRecorder(nopCloser{&buf}, replay.Header{Seed: 1}, input.Script(func(tick int) input.State {
+				return tt.states[tick]
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for tick := range tt.states {
+				rec.Poll(tick)
+			}
+			rec.Close()
+
+			//header, frames and the end marker
+			if got := bytes.Count(buf.Bytes(), []byte("\n")) - 2; got != tt.frames {
+				t.Fatalf("%d frames, want %d", got, tt.frames)
+			}
+
+			player, err := replay.Read(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for tick, want := range tt.states {
+				if got := player.Poll(tick); got != want {
+					t.Fatalf("tick %d played back %+v, want %+v", tick, got, want)
+				}
+			}
+		})
+	}
+}
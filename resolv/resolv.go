@@ -22,6 +22,26 @@ func GetObject(entry *donburi.Entry) *resolv.Object {
 	return components.Object.Get(entry)
 }
 
+// SetObject gives the entry its collision object, the object keeps the
+// entity in Data so collisions lead back to the entry.
 func SetObject(entry *donburi.Entry, obj *resolv.Object) {
+	obj.Data = entry.Entity()
 	components.Object.Set(entry, obj)
 }
+
+// Entry returns the live entry a collision object belongs to.
+func Entry(w donburi.World, obj *resolv.Object) (*donburi.Entry, bool) {
+	entity, ok := obj.Data.(donburi.Entity)
+	if !ok || !w.Valid(entity) {
+		return nil, false
+	}
+
+	return w.Entry(entity), true
+}
+
+// SetCollider puts the entry's object on a collision layer and tags the object
+// with it, the object has to be set first.
+func SetCollider(entry *donburi.Entry, layer, mask components.CollisionLayer) {
+	components.Collider.SetValue(entry, components.ColliderData{Layer: layer, Mask: mask})
+	GetObject(entry).AddTags(layer.Tag())
+}
@@ -0,0 +1,49 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/AndriiPets/FishGame/assets"
+)
+
+// BehaviourNode describes one node of a behaviour tree, node names are
+// resolved by the ai package.
+type BehaviourNode struct {
+	Node     string             `json:"node"`
+	Children []BehaviourNode    `json:"children"`
+	Params   map[string]float64 `json:"params"`
+}
+
+type Behaviour struct {
+	Name string        `json:"name"`
+	Root BehaviourNode `json:"root"`
+}
+
+type behaviourConfig struct {
+	Behaviours []Behaviour `json:"behaviours"`
+}
+
+var BehaviourMap = map[string]Behaviour{}
+
+// LoadBehaviours reads the embedded behaviour trees.
+func LoadBehaviours() error {
+	cfg := &behaviourConfig{}
+	if err := json.Unmarshal(assets.MustRead("config/behaviours.json"), cfg); err != nil {
+		return fmt.Errorf("behaviours.json: %w", err)
+	}
+
+	BehaviourMap = map[string]Behaviour{}
+
+	for _, b := range cfg.Behaviours {
+		if b.Name == "" {
+			return fmt.Errorf("behaviour without a name")
+		}
+		if _, ok := BehaviourMap[b.Name]; ok {
+			return fmt.Errorf("behaviour %q: defined twice", b.Name)
+		}
+		BehaviourMap[b.Name] = b
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package resources
+
+import (
+	"math"
+
+	"github.com/AndriiPets/FishGame/utils"
+)
+
+// Difficulty describes how hard a floor of a run is.
+type Difficulty struct {
+	Depth       int
+	EnemyBudget int                 //total cost of the enemies on the floor
+	Aggression  int                 //added to the enemies' aggression modifier
+	VisionScale float64             //multiplies how far enemies see
+	EnemyHealth int                 //added to the enemies' health
+	EnemyTypes  []utils.EnemyWeight //enemy types that can show up on the floor
+}
+
+// DifficultyAt returns the difficulty of the floor at depth, the first floor
+// is depth 0. Enemy types unlock at the depth set in the enemy data.
+func DifficultyAt(depth int) Difficulty {
+	d := float64(depth)
+
+	diff := Difficulty{
+		Depth:       depth,
+		EnemyBudget: int(math.Min(6+2*d, 24)),
+		Aggression:  depth,
+		VisionScale: math.Min(1+0.1*d, 1.8),
+		EnemyHealth: depth / 2,
+	}
+
+	for _, name := range EnemyTypes {
+		stats := EnemyMap[name]
+		if depth >= stats.Depth && stats.Weight > 0 {
+			diff.EnemyTypes = append(diff.EnemyTypes, utils.EnemyWeight{Type: name, Weight: stats.Weight, Cost: stats.Cost})
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,162 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/AndriiPets/FishGame/assets"
+)
+
+type AIType string
+
+const (
+	AIShooter AIType = "shooter" //runs its behaviour tree, the default
+	AIBrawler AIType = "brawler" //charges in and dashes at the player
+	AISniper  AIType = "sniper"  //stays at the edge of its vision and telegraphs its shots
+	AITurret  AIType = "turret"  //never moves
+	AISwarmer AIType = "swarmer" //moves in a pack with the swarmers around it
+)
+
+type EnemyStats struct {
+	Name       string     `json:"name"`
+	AI         AIType     `json:"ai"`
+	Behaviour  string     `json:"behaviour"` //behaviour tree, drives the enemy instead of its ai when set
+	Health     int        `json:"health"`
+	Speed      float64    `json:"speed"` //top speed, 0 never moves
+	Accel      float64    `json:"accel"`
+	Weapon     string     `json:"weapon"`
+	HoldRange  float64    `json:"hold_range"`
+	Vision     float64    `json:"vision"`
+	FOV        float64    `json:"fov"`        //degrees of the vision cone, 0 sees all around
+	Memory     float64    `json:"memory"`     //seconds a lost player is remembered
+	Aggression int        `json:"aggression"` //chance out of 100 to pull the trigger each tick
+	Action     int        `json:"action"`
+	NoDrop     bool       `json:"no_drop"` //the weapon is not left behind on death
+	Tint       [3]float64 `json:"tint"`    //color scale of the sprite, unset draws it as is
+
+	//encounters
+	Depth  int `json:"depth"`  //first floor the enemy shows up on
+	Weight int `json:"weight"` //relative chance of being placed
+	Cost   int `json:"cost"`   //share of the floor budget
+
+	//brawler
+	DashRange    float64 `json:"dash_range"`
+	DashSpeed    float64 `json:"dash_speed"`
+	DashTime     float64 `json:"dash_time"`
+	DashCooldown float64 `json:"dash_cooldown"`
+
+	//sniper
+	KeepDistance float64 `json:"keep_distance"` //backs off when the player gets closer
+	Telegraph    float64 `json:"telegraph"`     //seconds spent aiming before a shot
+
+	//swarmer
+	GroupRadius float64 `json:"group_radius"`
+}
+
+type enemyConfig struct {
+	Enemies []EnemyStats `json:"enemies"`
+}
+
+var (
+	EnemyMap = map[string]EnemyStats{}
+
+	// EnemyTypes lists the enemy names in file order.
+	EnemyTypes []string
+)
+
+// seconds a lost player is remembered when the data doesn't say
+const defaultMemory = 6.0
+
+// enemy animations are looked up as <name>_<state>. Every type past the orc
+// still points its entries at img/enemy_orc.png as placeholder art, their
+// tint is what tells them apart until they get sheets of their own.
+var enemyStates = []string{"idle", "run", "dead", "hit"}
+
+// LoadEnemies reads the embedded enemy stats. Sprites, weapons and behaviours
+// must be loaded first.
+func LoadEnemies() error {
+	cfg := &enemyConfig{}
+	if err := json.Unmarshal(assets.MustRead("config/enemies.json"), cfg); err != nil {
+		return fmt.Errorf("enemies.json: %w", err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	EnemyMap = map[string]EnemyStats{}
+	EnemyTypes = nil
+
+	for _, e := range cfg.Enemies {
+		if e.AI == "" {
+			e.AI = AIShooter
+		}
+		if e.Memory == 0 {
+			e.Memory = defaultMemory
+		}
+		EnemyMap[e.Name] = e
+		EnemyTypes = append(EnemyTypes, e.Name)
+	}
+
+	return nil
+}
+
+func (c *enemyConfig) validate() error {
+	enemies := map[string]bool{}
+
+	for _, e := range c.Enemies {
+		if e.Name == "" {
+			return fmt.Errorf("enemy without a name")
+		}
+		if enemies[e.Name] {
+			return fmt.Errorf("enemy %q: defined twice", e.Name)
+		}
+		switch e.AI {
+		case "", AIShooter:
+			if e.Behaviour == "" {
+				return fmt.Errorf("enemy %q: shooter needs a behaviour", e.Name)
+			}
+		case AITurret:
+		case AIBrawler:
+			if e.DashRange <= 0 || e.DashSpeed <= 0 || e.DashTime <= 0 {
+				return fmt.Errorf("enemy %q: brawler needs a positive dash_range, dash_speed and dash_time", e.Name)
+			}
+		case AISniper:
+			if e.KeepDistance <= 0 || e.Telegraph <= 0 {
+				return fmt.Errorf("enemy %q: sniper needs a positive keep_distance and telegraph", e.Name)
+			}
+		case AISwarmer:
+			if e.GroupRadius <= 0 {
+				return fmt.Errorf("enemy %q: swarmer needs a positive group_radius", e.Name)
+			}
+		default:
+			return fmt.Errorf("enemy %q: unknown ai %q", e.Name, e.AI)
+		}
+		if e.Health <= 0 {
+			return fmt.Errorf("enemy %q: health must be positive", e.Name)
+		}
+		if e.Speed < 0 || e.Accel < 0 || e.Vision <= 0 {
+			return fmt.Errorf("enemy %q: speed and accel must not be negative and vision must be positive", e.Name)
+		}
+		if e.FOV < 0 || e.FOV > 360 || e.Memory < 0 {
+			return fmt.Errorf("enemy %q: fov must be within 0 and 360 and memory must not be negative", e.Name)
+		}
+		if e.Depth < 0 || e.Weight < 0 || e.Cost < 0 {
+			return fmt.Errorf("enemy %q: depth, weight and cost must not be negative", e.Name)
+		}
+		if _, ok := BehaviourMap[e.Behaviour]; e.Behaviour != "" && !ok {
+			return fmt.Errorf("enemy %q: unknown behaviour %q", e.Name, e.Behaviour)
+		}
+		if _, ok := WeaponMap[e.Weapon]; !ok {
+			return fmt.Errorf("enemy %q: unknown weapon %q", e.Name, e.Weapon)
+		}
+		for _, state := range enemyStates {
+			if !assets.HasAnimation(e.Name + "_" + state) {
+				return fmt.Errorf("enemy %q: missing animation %s_%s", e.Name, e.Name, state)
+			}
+		}
+		enemies[e.Name] = true
+	}
+
+	return nil
+}
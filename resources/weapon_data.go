@@ -1,41 +1,276 @@
 package resources
 
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/AndriiPets/FishGame/assets"
+	"github.com/AndriiPets/FishGame/config"
+)
+
+type WeaponKind string
+
+const (
+	WeaponProjectile WeaponKind = "projectile" //spawns bullets, the default
+	WeaponHitscan    WeaponKind = "hitscan"    //instant ray cast through the space
+	WeaponMelee      WeaponKind = "melee"      //hits everything inside an arc in front of the holder
+	WeaponCharge     WeaponKind = "charge"     //projectile released when fire is let go
+)
+
+type FireMode string
+
+const (
+	FireAuto  FireMode = "auto"  //keeps firing while the trigger is held, the default
+	FireSemi  FireMode = "semi"  //one shot per trigger pull
+	FireBurst FireMode = "burst" //BurstCount shots per trigger pull
+)
+
 type Weapon struct {
-	Type     string
-	Cooldown float64
-	Bullet   string
+	Name         string     `json:"name"`
+	Kind         WeaponKind `json:"kind"`
+	Cooldown     float64    `json:"cooldown"`
+	Projectile   string     `json:"projectile"`
+	Damage       int        `json:"damage"`
+	Spread       float64    `json:"spread"` //degrees
+	Pellets      int        `json:"pellets"`
+	Knockback    float64    `json:"knockback"`
+	Sprite       string     `json:"sprite"`
+	MuzzleFlash  string     `json:"muzzle_flash"`
+	Player       bool       `json:"player"`        //found as a pickup by the player
+	FriendlyFire bool       `json:"friendly_fire"` //bullets also hit the shooter's allies
+	Noise        float64    `json:"noise"`         //pixels a shot can be heard from, 0 is silent
+
+	Range      float64 `json:"range"`       //hitscan and melee reach in pixels
+	Arc        float64 `json:"arc"`         //melee swing in degrees
+	ChargeTime float64 `json:"charge_time"` //seconds of holding fire to reach full charge
+	ChargeMax  float64 `json:"charge_max"`  //speed and damage multiplier at full charge
+
+	FireMode   FireMode `json:"fire_mode"`
+	BurstCount int      `json:"burst_count"`
+	BurstDelay float64  `json:"burst_delay"` //seconds between shots of a burst
+	Magazine   int      `json:"magazine"`    //shots before reloading, 0 never reloads
+	Reserve    int      `json:"reserve"`     //spare ammo the weapon starts with
+	ReloadTime float64  `json:"reload_time"`
 }
 
 type Projectile struct {
-	Size  float64
-	Speed float64
-}
-
-var WeaponMap = map[string]Weapon{
-	"default": {
-		Type:     "default",
-		Cooldown: 0.2,
-		Bullet:   "normal",
-	},
-	"bouncer": {
-		Type:     "bouncer",
-		Cooldown: 0.5,
-		Bullet:   "bounce",
-	},
-	"enemy_default": {
-		Type:     "default",
-		Cooldown: 0.5,
-		Bullet:   "normal",
-	},
-}
-
-var ProjectileMap = map[string]Projectile{
-	"normal": {
-		Size:  8,
-		Speed: 15.0,
-	},
-	"bounce": {
-		Size:  8,
-		Speed: 15.0,
-	},
+	Name     string  `json:"name"`
+	Size     float64 `json:"size"`
+	Speed    float64 `json:"speed"`
+	Lifetime float64 `json:"lifetime"` //seconds
+	Bounces  int     `json:"bounces"`
+	Sprite   string  `json:"sprite"`
+	Blast    *Blast  `json:"blast"` //explodes where it stops, nil doesn't
+}
+
+// Blast is the area damage of an explosion, damage and knockback fall off to
+// nothing at the radius.
+type Blast struct {
+	Radius    float64 `json:"radius"`
+	Damage    int     `json:"damage"`
+	Knockback float64 `json:"knockback"`
+}
+
+type weaponConfig struct {
+	Weapons     []Weapon     `json:"weapons"`
+	Projectiles []Projectile `json:"projectiles"`
+}
+
+var (
+	WeaponMap     = map[string]Weapon{}
+	ProjectileMap = map[string]Projectile{}
+
+	// PlayerWeapons lists the weapons the player can find, in file order. The
+	// player starts with the first one.
+	PlayerWeapons []string
+)
+
+// LoadWeapons reads the embedded weapon definitions, merges the on-disk
+// override from config if there is one, and validates the result. Sprites must
+// be loaded first.
+func LoadWeapons() error {
+	cfg := &weaponConfig{}
+	if err := json.Unmarshal(assets.MustRead("config/weapons.json"), cfg); err != nil {
+		return fmt.Errorf("weapons.json: %w", err)
+	}
+
+	if path := config.C.WeaponsFile; path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		override := &weaponConfig{}
+		if err := json.Unmarshal(b, override); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		cfg.merge(override)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	if !cfg.hasPlayerWeapon() {
+		return fmt.Errorf("no weapon is marked for the player")
+	}
+
+	WeaponMap = map[string]Weapon{}
+	ProjectileMap = map[string]Projectile{}
+	PlayerWeapons = nil
+
+	for _, p := range cfg.Projectiles {
+		ProjectileMap[p.Name] = p
+	}
+
+	for _, w := range cfg.Weapons {
+		if w.Kind == "" {
+			w.Kind = WeaponProjectile
+		}
+		if w.FireMode == "" {
+			w.FireMode = FireAuto
+		}
+		WeaponMap[w.Name] = w
+		if w.Player {
+			PlayerWeapons = append(PlayerWeapons, w.Name)
+		}
+	}
+
+	return nil
+}
+
+// merge replaces definitions with the same name and appends new ones.
+func (c *weaponConfig) merge(other *weaponConfig) {
+	for _, w := range other.Weapons {
+		replaced := false
+		for i := range c.Weapons {
+			if c.Weapons[i].Name == w.Name {
+				c.Weapons[i] = w
+				replaced = true
+			}
+		}
+		if !replaced {
+			c.Weapons = append(c.Weapons, w)
+		}
+	}
+
+	for _, p := range other.Projectiles {
+		replaced := false
+		for i := range c.Projectiles {
+			if c.Projectiles[i].Name == p.Name {
+				c.Projectiles[i] = p
+				replaced = true
+			}
+		}
+		if !replaced {
+			c.Projectiles = append(c.Projectiles, p)
+		}
+	}
+}
+
+func (c *weaponConfig) validate() error {
+	projectiles := map[string]bool{}
+
+	for _, p := range c.Projectiles {
+		if p.Name == "" {
+			return fmt.Errorf("projectile without a name")
+		}
+		if projectiles[p.Name] {
+			return fmt.Errorf("projectile %q: defined twice", p.Name)
+		}
+		if p.Size <= 0 || p.Speed <= 0 || p.Lifetime <= 0 {
+			return fmt.Errorf("projectile %q: size, speed and lifetime must be positive", p.Name)
+		}
+		if p.Bounces < 0 {
+			return fmt.Errorf("projectile %q: bounces must not be negative", p.Name)
+		}
+		if !assets.HasAnimation(p.Sprite) {
+			return fmt.Errorf("projectile %q: unknown sprite animation %q", p.Name, p.Sprite)
+		}
+		if p.Blast != nil && (p.Blast.Radius <= 0 || p.Blast.Damage < 0 || p.Blast.Knockback < 0) {
+			return fmt.Errorf("projectile %q: blast needs a positive radius, damage and knockback must not be negative", p.Name)
+		}
+		projectiles[p.Name] = true
+	}
+
+	weapons := map[string]bool{}
+
+	for _, w := range c.Weapons {
+		if w.Name == "" {
+			return fmt.Errorf("weapon without a name")
+		}
+		if weapons[w.Name] {
+			return fmt.Errorf("weapon %q: defined twice", w.Name)
+		}
+		switch w.Kind {
+		case "", WeaponProjectile:
+		case WeaponHitscan:
+			if w.Range <= 0 {
+				return fmt.Errorf("weapon %q: hitscan needs a positive range", w.Name)
+			}
+		case WeaponMelee:
+			if w.Range <= 0 || w.Arc <= 0 {
+				return fmt.Errorf("weapon %q: melee needs a positive range and arc", w.Name)
+			}
+		case WeaponCharge:
+			if w.ChargeTime <= 0 || w.ChargeMax < 1 {
+				return fmt.Errorf("weapon %q: charge needs a positive charge_time and charge_max of at least 1", w.Name)
+			}
+		default:
+			return fmt.Errorf("weapon %q: unknown kind %q", w.Name, w.Kind)
+		}
+		if w.Noise < 0 {
+			return fmt.Errorf("weapon %q: noise must not be negative", w.Name)
+		}
+		switch w.FireMode {
+		case "", FireAuto, FireSemi:
+		case FireBurst:
+			if w.BurstCount < 2 || w.BurstDelay <= 0 {
+				return fmt.Errorf("weapon %q: burst needs a burst_count of at least 2 and a positive burst_delay", w.Name)
+			}
+		default:
+			return fmt.Errorf("weapon %q: unknown fire mode %q", w.Name, w.FireMode)
+		}
+		if w.Magazine < 0 || w.Reserve < 0 {
+			return fmt.Errorf("weapon %q: magazine and reserve must not be negative", w.Name)
+		}
+		if w.Magazine > 0 && w.ReloadTime <= 0 {
+			return fmt.Errorf("weapon %q: a magazine needs a positive reload_time", w.Name)
+		}
+		if w.usesProjectile() && !projectiles[w.Projectile] {
+			return fmt.Errorf("weapon %q: unknown projectile %q", w.Name, w.Projectile)
+		}
+		if w.Cooldown <= 0 {
+			return fmt.Errorf("weapon %q: cooldown must be positive", w.Name)
+		}
+		if w.Pellets < 1 {
+			return fmt.Errorf("weapon %q: needs at least one pellet", w.Name)
+		}
+		if w.Spread < 0 || w.Damage < 0 || w.Knockback < 0 {
+			return fmt.Errorf("weapon %q: spread, damage and knockback must not be negative", w.Name)
+		}
+		if !assets.HasAnimation(w.Sprite) {
+			return fmt.Errorf("weapon %q: unknown sprite animation %q", w.Name, w.Sprite)
+		}
+		if w.MuzzleFlash != "" && !assets.HasAnimation(w.MuzzleFlash) {
+			return fmt.Errorf("weapon %q: unknown muzzle flash animation %q", w.Name, w.MuzzleFlash)
+		}
+		weapons[w.Name] = true
+	}
+
+	return nil
+}
+
+func (w Weapon) usesProjectile() bool {
+	return w.Kind == "" || w.Kind == WeaponProjectile || w.Kind == WeaponCharge
+}
+
+func (c *weaponConfig) hasPlayerWeapon() bool {
+	for _, w := range c.Weapons {
+		if w.Player {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,54 @@
+package scenes
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+type GameOverScene struct {
+	manager *Manager
+	menu    *menu
+	summary Summary
+}
+
+func NewGameOverScene(m *Manager, game *MainScene) *GameOverScene {
+	gs := &GameOverScene{
+		manager: m,
+		summary: game.Summary(),
+	}
+
+	gs.menu = &menu{
+		title: "GAME OVER",
+		items: []menuItem{
+			item("Try again", func() {
+				m.Replace(game.Next())
+			}),
+			item("Title", func() {
+				m.Replace(NewTitleScene(m, game.input, game.NextSeed()))
+			}),
+		},
+		input: game.input,
+	}
+
+	return gs
+}
+
+func (gs *GameOverScene) Update() {
+	gs.menu.update()
+}
+
+func (gs *GameOverScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{20, 20, 20, 255})
+
+	seconds := float64(gs.summary.Ticks) / float64(config.TickRate)
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf(
+		"reached floor %d\nsurvived %.1fs\nenemies killed %d\nseed %d",
+		gs.summary.Depth+1, seconds, gs.summary.Kills, gs.summary.Seed,
+	), 48, 140)
+
+	gs.menu.draw(screen, 48, 48)
+}
@@ -2,8 +2,10 @@ package scenes
 
 import (
 	//"fmt"
+	"errors"
 	"fmt"
 	"image/color"
+	"log"
 	"sync"
 
 	"github.com/AndriiPets/FishGame/archetypes"
@@ -12,12 +14,16 @@ import (
 	"github.com/AndriiPets/FishGame/config"
 	"github.com/AndriiPets/FishGame/events"
 	"github.com/AndriiPets/FishGame/factory"
+	"github.com/AndriiPets/FishGame/input"
 	"github.com/AndriiPets/FishGame/layers"
 	dresolv "github.com/AndriiPets/FishGame/resolv"
+	"github.com/AndriiPets/FishGame/resources"
 	"github.com/AndriiPets/FishGame/systems"
 	"github.com/AndriiPets/FishGame/systems/ai"
 	"github.com/AndriiPets/FishGame/utils"
+	"github.com/AndriiPets/FishGame/utils/dngn"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/solarlune/resolv"
 
 	"github.com/yohamta/donburi"
@@ -26,78 +32,307 @@ import (
 
 type MainScene struct {
 	ecs         *ecs.ECS
-	once        sync.Once
 	WorldScreen *ebiten.Image
 	Time        *ecs.Time
+
+	manager  *Manager //nil for headless scenes
+	headless bool
+	input    input.Source
+	seed     int64
+
+	run          *RunState
+	startTick    int //clock tick the floor starts at, keeps ticks increasing across restarts for replays
+	gameOver     bool
+	gameOverTick int
+	kills        int //enemies killed on this floor
+	exitReached  bool
+	err          error //why the floor could not be built, the scene stands still
+}
+
+// Summary describes how a run went.
+type Summary struct {
+	Seed  int64 //seed of the first floor
+	Depth int   //floors cleared
+	Ticks int   //ticks survived
+	Kills int
+}
+
+// seconds after the player's death before the game over screen or a restart
+const restartDelay = 1.0
+
+func NewMainScene(m *Manager, source input.Source, seed int64) *MainScene {
+	ms := &MainScene{
+		manager: m,
+		input:   source,
+		seed:    seed,
+		run:     newRun(seed, 0),
+	}
+	ms.configure()
+
+	return ms
+}
+
+// NewHeadlessScene creates a scene that can be stepped without an ebiten window.
+// Renderers are skipped and input is read from the given source. The same seed
+// and input produce the same simulation.
+func NewHeadlessScene(source input.Source, seed int64) *MainScene {
+	ms := &MainScene{
+		headless: true,
+		input:    source,
+		seed:     seed,
+		run:      newRun(seed, 0),
+	}
+	ms.configure()
+
+	return ms
+}
+
+// Next starts a new run continuing this scene's clock. The new seed comes
+// from the current run so replays stay deterministic.
+func (ms *MainScene) Next() *MainScene {
+	seed, tick := ms.NextSeed(), systems.GetClock(ms.ecs).Tick
+
+	next := &MainScene{
+		manager:   ms.manager,
+		headless:  ms.headless,
+		input:     ms.input,
+		seed:      seed,
+		run:       newRun(seed, tick),
+		startTick: tick,
+	}
+	next.configure()
+
+	return next
+}
+
+// NextFloor creates the floor below this one, the player keeps their health
+// and weapons.
+func (ms *MainScene) NextFloor() *MainScene {
+	ms.leaveFloor()
+
+	next := &MainScene{
+		manager:   ms.manager,
+		headless:  ms.headless,
+		input:     ms.input,
+		seed:      ms.NextSeed(),
+		run:       ms.run,
+		startTick: systems.GetClock(ms.ecs).Tick,
+	}
+	next.configure()
+
+	return next
+}
+
+// leaveFloor moves the player and this floor's kills into the run.
+func (ms *MainScene) leaveFloor() {
+	if player, ok := components.Player.First(ms.ecs.World); ok {
+		ms.run.save(player)
+	}
+	ms.run.Kills += ms.kills
+	ms.run.Depth++
+}
+
+// NextSeed draws the seed for the level after this one from the run's randomness.
+func (ms *MainScene) NextSeed() int64 {
+	return systems.GetRandom(ms.ecs).Int63()
 }
 
 func (ms *MainScene) Update() {
-	ms.once.Do(ms.configure)
+	if ms.err != nil {
+		return
+	}
+
 	ms.ecs.Update()
 	ms.ecs.Time.Update()
 	ms.Time.Update()
+
+	in := systems.GetInput(ms.ecs)
+
+	if ms.manager != nil && !ms.gameOver && in.PausePressed() {
+		ms.manager.Push(NewPauseScene(ms.manager, ms))
+		return
+	}
+
+	if ms.gameOver && systems.GetClock(ms.ecs).Since(ms.gameOverTick) >= restartDelay {
+		switch {
+		case ms.manager != nil:
+			ms.manager.Replace(NewGameOverScene(ms.manager, ms))
+			ms.gameOver = false
+		case in.FirePressed():
+			ms.restart()
+		}
+		return
+	}
+
+	if ms.exitReached {
+		ms.exitReached = false
+
+		if ms.manager != nil {
+			ms.manager.Replace(ms.NextFloor())
+		} else {
+			ms.descend()
+		}
+	}
+}
+
+// restart starts a new run in place, headless runs have no manager to
+// hand a new scene to.
+func (ms *MainScene) restart() {
+	ms.startTick = systems.GetClock(ms.ecs).Tick
+	ms.seed = ms.NextSeed()
+	ms.run = newRun(ms.seed, ms.startTick)
+	ms.gameOver = false
+	ms.kills = 0
+	ms.configure()
+}
+
+// descend builds the next floor in place.
+func (ms *MainScene) descend() {
+	ms.leaveFloor()
+	ms.startTick = systems.GetClock(ms.ecs).Tick
+	ms.seed = ms.NextSeed()
+	ms.kills = 0
+	ms.configure()
+}
+
+// Err reports why the current floor could not be built, nil when it was.
+func (ms *MainScene) Err() error {
+	return ms.err
+}
+
+// GameOver reports whether the player is dead.
+func (ms *MainScene) GameOver() bool {
+	return ms.gameOver
+}
+
+func (ms *MainScene) Summary() Summary {
+	return Summary{
+		Seed:  ms.run.Seed,
+		Depth: ms.run.Depth,
+		Ticks: systems.GetClock(ms.ecs).Tick - ms.run.StartTick,
+		Kills: ms.run.Kills + ms.kills,
+	}
+}
+
+func (ms *MainScene) onDeath(w donburi.World, event events.Death) {
+	switch {
+	case event.Entry.HasComponent(components.Player):
+		ms.gameOver = true
+		ms.gameOverTick = systems.GetClock(ms.ecs).Tick
+	case event.Entry.HasComponent(components.Enemy):
+		ms.kills++
+	}
+}
+
+func (ms *MainScene) onFloorExit(w donburi.World, event events.FloorExit) {
+	if !ms.gameOver {
+		ms.exitReached = true
+	}
+}
+
+// Run steps the simulation for the given number of ticks.
+func (ms *MainScene) Run(ticks int) {
+	for i := 0; i < ticks; i++ {
+		ms.Update()
+	}
+}
+
+// ECS exposes the underlying ecs.
+func (ms *MainScene) ECS() *ecs.ECS {
+	return ms.ecs
 }
 
 func (ms *MainScene) Draw(screen *ebiten.Image) {
+	if ms.headless {
+		return
+	}
+	if ms.err != nil {
+		ebitenutil.DebugPrintAt(screen, ms.err.Error(), 4, 4)
+		return
+	}
+
 	ms.WorldScreen.Fill(color.RGBA{194, 178, 128, 255})
 	ms.ecs.Draw(ms.WorldScreen)
 
 	//fmt.Println(systems.CameraString(ms.ecs))
 	//fmt.Println(systems.PlayerString(ms.ecs))
 	systems.CameraRender(ms.WorldScreen, screen)
+
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("floor %d", ms.run.Depth+1), 4, config.C.ScreenHeight-16)
+
+	if ms.gameOver {
+		ebitenutil.DebugPrintAt(screen, "YOU DIED", config.C.ScreenWidth/2-24, config.C.ScreenHeight/2-8)
+	}
 }
 
 func (ms *MainScene) configure() {
 
 	ms.Time = ecs.NewTime()
+	ms.err = nil
 
 	ecs := ecs.NewECS(donburi.NewWorld())
 
 	loadAssets()
 
+	if ms.input == nil {
+		ms.input = input.NewDevice(input.DefaultBindings())
+	}
+
 	factory.CreateCamera(ecs)
-	ms.WorldScreen = ebiten.NewImage(config.C.WorldWidth, config.C.WorldHeigth)
+	clock := factory.CreateClock(ecs, 1/float64(config.TickRate))
+	components.Clock.Get(clock).Tick = ms.startTick
+	factory.CreateInput(ecs, ms.input)
+	factory.CreateRandom(ecs, ms.seed)
 
 	events.SetupEvents(ecs)
+	events.DeathEvent.Subscribe(ecs.World, systems.OnDeath(ecs))
+	events.DeathEvent.Subscribe(ecs.World, systems.OnBlockDestroyed(ecs))
+	events.DeathEvent.Subscribe(ecs.World, ms.onDeath)
+	events.FloorExitEvent.Subscribe(ecs.World, ms.onFloorExit)
+	events.NoiseEvent.Subscribe(ecs.World, ai.OnNoise(ecs))
+	events.TriggerEnterEvent.Subscribe(ecs.World, systems.OnExitTrigger(ecs))
+	events.TriggerEnterEvent.Subscribe(ecs.World, systems.OnTrapTrigger(ecs))
+	events.TriggerStayEvent.Subscribe(ecs.World, systems.OnPickupTrigger(ecs))
+	events.TriggerStayEvent.Subscribe(ecs.World, systems.OnRoomTrigger(ecs))
 
+	ecs.AddSystem(systems.UpdateClock)
+	ecs.AddSystem(systems.UpdateInput)
 	ecs.AddSystem(systems.UpdateObjects)
 	ecs.AddSystem(systems.UpdatePlayer)
+	ecs.AddSystem(systems.UpdateTriggers)
+	ecs.AddSystem(systems.UpdateRooms)
 	ecs.AddSystem(systems.UpdateAttackVector)
 	ecs.AddSystem(systems.UpdateCollisions)
 	ecs.AddSystem(systems.CameraUpdate)
 	ecs.AddSystem(systems.UpdateShooters)
+	ecs.AddSystem(systems.UpdateBullets)
+	ecs.AddSystem(systems.UpdateExplosions)
 	ecs.AddSystem(systems.UpdateDespawnable)
 	ecs.AddSystem(systems.UpdateAnimations)
 	ecs.AddSystem(systems.UpdateWeaponSprite)
 	ecs.AddSystem(systems.UpdateHealth)
+	ecs.AddSystem(systems.UpdateCorpses)
 	ecs.AddSystem(systems.UpdateEnemies)
+	ecs.AddSystem(systems.UpdatePathFinding)
+	ecs.AddSystem(ai.UpdatePerception)
 	ecs.AddSystem(ai.UpdateAI)
+	ecs.AddSystem(ai.UpdateNoises)
 	ecs.AddSystem(systems.UpdateParticles)
+	ecs.AddSystem(systems.UpdateTracers)
 
 	ecs.AddSystem(events.UpdateEvents)
 
-	ecs.AddSystem(systems.UpdateSettings)
-
-	//ecs.AddRenderer(layers.Default, systems.DrawWall)
-	ecs.AddRenderer(layers.Default, systems.DrawPlayer)
-	//ecs.AddRenderer(layers.Default, systems.DrawWeaponFlash)
-	ecs.AddRenderer(layers.Default, systems.DrawEnemyes)
-	ecs.AddRenderer(layers.System, ai.DrawDebugAi)
-	//ecs.AddRenderer(layers.Default, systems.DrawBullet)
-
-	//Draw animations for each layer
-	ecs.AddRenderer(layers.Player, systems.DrawAnimation(layers.Player))
-	ecs.AddRenderer(layers.Actors, systems.DrawAnimation(layers.Actors))
-	ecs.AddRenderer(layers.Architecture, systems.DrawAnimation(layers.Architecture))
-	ecs.AddRenderer(layers.Interactables, systems.DrawAnimation(layers.Interactables))
-	ecs.AddRenderer(layers.FX, systems.DrawAnimation(layers.FX))
-	ecs.AddRenderer(layers.System, systems.DrawDebug)
-	//
-
 	ms.ecs = ecs
 
-	world := utils.NewWorldMap()
-	world.GenerateMap(utils.BSP)
+	if !ms.headless {
+		ms.configureRendering()
+	}
+
+	world, err := ms.loadWorld()
+	if err != nil {
+		ms.err = err
+		return
+	}
 
 	//gw, gh := float64(config.C.WorldWidth), float64(config.C.WorldHeigth)
 
@@ -109,23 +344,74 @@ func (ms *MainScene) configure() {
 			//fmt.Println(posX, posY)
 			//var block *donburi.Entry
 			if val == 'x' {
-				dresolv.Add(space, factory.CreateWall(ms.ecs, resolv.NewObject(float64(posX), float64(posY), float64(config.BlockSize), float64(config.BlockSize)), components.BlockWall))
+				obj := resolv.NewObject(float64(posX), float64(posY), float64(config.BlockSize), float64(config.BlockSize))
+				if world.Destructible[dngn.Position{X: x, Y: y}] {
+					dresolv.Add(space, factory.CreateDestructibleWall(ms.ecs, obj))
+				} else {
+					dresolv.Add(space, factory.CreateWall(ms.ecs, obj, components.BlockWall))
+				}
 			}
 			if val == 'e' {
-				dresolv.Add(space, factory.CreateEnemy(ms.ecs, float64(posX), float64(posY), components.EnemyTypeGrunt))
+				ms.spawnEnemy(space, float64(posX), float64(posY), "")
 			}
 			if val == 'P' {
 				fmt.Println(posX, posY)
 				dresolv.Add(space, factory.CreatePlayer(ms.ecs, float64(posX), float64(posY)))
 			}
+			if val == 'w' {
+				//generated pickups roll one of the player's weapons
+				weapons := resources.PlayerWeapons
+				weapon := weapons[systems.GetRandom(ms.ecs).Intn(len(weapons))]
+				dresolv.Add(space, factory.CreateWeaponPickup(ms.ecs, float64(posX), float64(posY), components.NewWeaponSlot(weapon), 0, 0))
+			}
+			if val == '>' {
+				dresolv.Add(space, factory.CreateExit(ms.ecs, float64(posX), float64(posY)))
+			}
 
 		}
 	}
 
+	for _, spawn := range world.Spawns {
+		switch spawn.Kind {
+		case utils.SpawnPlayer:
+			dresolv.Add(space, factory.CreatePlayer(ms.ecs, spawn.X, spawn.Y))
+		case utils.SpawnEnemy:
+			ms.spawnEnemy(space, spawn.X, spawn.Y, spawn.Type)
+		case utils.SpawnPickup:
+			if _, ok := resources.WeaponMap[spawn.Type]; !ok {
+				log.Printf("pickup has unknown weapon %q, skipping", spawn.Type)
+				continue
+			}
+			dresolv.Add(space, factory.CreateWeaponPickup(ms.ecs, spawn.X, spawn.Y, components.NewWeaponSlot(spawn.Type), 0, 0))
+		case utils.SpawnExit:
+			dresolv.Add(space, factory.CreateExit(ms.ecs, spawn.X, spawn.Y))
+		case utils.SpawnRoom:
+			dresolv.Add(space, factory.CreateRoom(ms.ecs, spawn.X, spawn.Y, spawn.W, spawn.H))
+		case utils.SpawnTrap:
+			dresolv.Add(space, factory.CreateTrap(ms.ecs, spawn.X, spawn.Y, spawn.W, spawn.H))
+		case utils.SpawnPrompt:
+			dresolv.Add(space, factory.CreatePrompt(ms.ecs, spawn.X, spawn.Y, spawn.W, spawn.H, spawn.Text))
+		case utils.SpawnBarrel:
+			dresolv.Add(space, factory.CreateBarrel(ms.ecs, spawn.X, spawn.Y))
+		default:
+			log.Printf("spawn %s is not supported yet, skipping", spawn.Kind)
+		}
+	}
+
+	if player, ok := components.Player.First(ms.ecs.World); ok {
+		ms.run.restore(player)
+	}
+
 	//create pathfinder object
 	pathfinder := utils.NewPathFinder()
 	pathfinder.GenerateLayout(world.Map.Data, 'x')
 
+	//barrels stand in the way until they blow up
+	components.Explosive.Each(ms.ecs.World, func(e *donburi.Entry) {
+		o := dresolv.GetObject(e)
+		pathfinder.SetWalkable(pathfinder.CoordToGrid(o.Position.X+o.Size.X/2, o.Position.Y+o.Size.Y/2), false)
+	})
+
 	//make avaliable to components by wrapping in entity
 	pFinder := archetypes.PathFinder.Spawn(ms.ecs)
 	components.PathFinder.Set(pFinder, pathfinder)
@@ -140,12 +426,133 @@ func (ms *MainScene) configure() {
 	//)
 }
 
-func loadAssets() {
-	for _, fn := range []func() error{
-		assets.Load,
-	} {
-		if err := fn(); err != nil {
-			panic(err)
+// configureRendering sets up everything that needs a window to draw into.
+func (ms *MainScene) configureRendering() {
+	ms.WorldScreen = ebiten.NewImage(config.C.WorldWidth, config.C.WorldHeigth)
+
+	ms.ecs.AddSystem(systems.UpdateSettings)
+
+	//ecs.AddRenderer(layers.Default, systems.DrawWall)
+	ms.ecs.AddRenderer(layers.Default, systems.DrawPlayer)
+	//ecs.AddRenderer(layers.Default, systems.DrawWeaponFlash)
+	ms.ecs.AddRenderer(layers.Default, systems.DrawEnemyes)
+	ms.ecs.AddRenderer(layers.System, ai.DrawDebugAi)
+	//ecs.AddRenderer(layers.Default, systems.DrawBullet)
+
+	//Draw animations for each layer
+	ms.ecs.AddRenderer(layers.Player, systems.DrawAnimation(layers.Player))
+	ms.ecs.AddRenderer(layers.Actors, systems.DrawAnimation(layers.Actors))
+	ms.ecs.AddRenderer(layers.Architecture, systems.DrawAnimation(layers.Architecture))
+	ms.ecs.AddRenderer(layers.Architecture, systems.DrawExits)
+	ms.ecs.AddRenderer(layers.Architecture, systems.DrawTraps)
+	ms.ecs.AddRenderer(layers.Interactables, systems.DrawAnimation(layers.Interactables))
+	ms.ecs.AddRenderer(layers.FX, systems.DrawAnimation(layers.FX))
+	ms.ecs.AddRenderer(layers.FX, systems.DrawTracers)
+	ms.ecs.AddRenderer(layers.FX, ai.DrawTelegraphs)
+	ms.ecs.AddRenderer(layers.FX, systems.DrawPrompts)
+	ms.ecs.AddRenderer(layers.System, systems.DrawDebug)
+	//
+}
+
+// spawnEnemy creates an enemy scaled to the floor's depth, an empty type is
+// the default grunt.
+func (ms *MainScene) spawnEnemy(space *donburi.Entry, x, y float64, enemyType string) {
+	diff := resources.DifficultyAt(ms.run.Depth)
+	if enemyType == "" {
+		enemyType = string(components.EnemyTypeGrunt)
+	}
+
+	if _, ok := resources.EnemyMap[enemyType]; !ok {
+		log.Printf("unknown enemy type %q, skipping", enemyType)
+		return
+	}
+
+	enemy := factory.CreateEnemy(ms.ecs, x, y, components.EnemyType(enemyType))
+
+	ai := components.AI.Get(enemy)
+	ai.AgressionModifier += diff.Aggression
+	ai.VisionRadius *= diff.VisionScale
+	components.Health.Get(enemy).Ammount += diff.EnemyHealth
+
+	dresolv.Add(space, enemy)
+}
+
+// loadWorld loads the configured tiled level or generates a new one from the scene seed.
+func (ms *MainScene) loadWorld() (*utils.World, error) {
+	if config.C.Level == "" {
+		opts := utils.DefaultMapOptions()
+		opts.Seed = ms.seed
+		diff := resources.DifficultyAt(ms.run.Depth)
+		opts.EnemyBudget = diff.EnemyBudget
+		opts.EnemyTypes = diff.EnemyTypes
+		return generateWorld(opts)
+	}
+
+	return loadLevel(config.C.Level)
+}
+
+// CheckLevel loads the configured tiled level once so a missing or broken
+// -level is reported before the game starts.
+func CheckLevel() error {
+	if config.C.Level == "" {
+		return nil
+	}
+
+	_, err := loadLevel(config.C.Level)
+	return err
+}
+
+func loadLevel(name string) (*utils.World, error) {
+	data, err := assets.ReadLevel(name)
+	if err != nil {
+		return nil, fmt.Errorf("level %q: %w", name, err)
+	}
+
+	world, err := utils.LoadTiledMap(data)
+	if err != nil {
+		return nil, fmt.Errorf("level %q: %w", name, err)
+	}
+
+	return world, nil
+}
+
+// generateWorld retries with the following seeds when a layout turns out degenerate,
+// so a given seed still always leads to the same level. Broken options are
+// returned as they are.
+func generateWorld(opts utils.MapOptions) (*utils.World, error) {
+	seed := opts.Seed
+	for attempt := 0; attempt < 10; attempt++ {
+		world := utils.NewWorldMap()
+		err := world.GenerateMap(opts)
+		if err == nil {
+			return world, nil
+		}
+
+		if !errors.Is(err, utils.ErrDegenerateMap) {
+			return nil, err
 		}
+		log.Println(err)
+		opts.Seed++
 	}
+
+	return nil, fmt.Errorf("could not generate a level from seed %d", seed)
+}
+
+var assetsOnce sync.Once
+
+// loadAssets reads sprites and data files the first time a scene needs them.
+func loadAssets() {
+	assetsOnce.Do(func() {
+		for _, fn := range []func() error{
+			assets.Load,
+			resources.LoadWeapons,
+			resources.LoadBehaviours,
+			ai.LoadBehaviours,
+			resources.LoadEnemies,
+		} {
+			if err := fn(); err != nil {
+				panic(err)
+			}
+		}
+	})
 }
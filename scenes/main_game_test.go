@@ -0,0 +1,211 @@
+package scenes
+
+import (
+	"testing"
+
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/input"
+	"github.com/AndriiPets/FishGame/systems"
+	"github.com/AndriiPets/FishGame/tags"
+	"github.com/AndriiPets/FishGame/utils"
+	"github.com/solarlune/resolv"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/features/math"
+)
+
+// strafe walks in a square while shooting to the right.
+var strafe = input.Script(func(tick int) input.State {
+	dirs := []math.Vec2{
+		math.NewVec2(1, 0),
+		math.NewVec2(0, 1),
+		math.NewVec2(-1, 0),
+		math.NewVec2(0, -1),
+	}
+
+	return input.State{
+		Move: dirs[(tick/60)%len(dirs)],
+		Aim:  math.NewVec2(1, 0),
+		Fire: tick%2 == 0,
+	}
+})
+
+// endState is what two runs are compared on.
+type endState struct {
+	Tick    int
+	Player  resolv.Vector
+	Health  int
+	Dead    bool
+	Enemies []resolv.Vector
+	Summary Summary
+}
+
+func snapshot(ms *MainScene) endState {
+	world := ms.ECS().World
+	player := components.Player.MustFirst(world)
+	health := components.Health.Get(player)
+
+	state := endState{
+		Tick:    systems.GetClock(ms.ECS()).Tick,
+		Player:  components.Object.Get(player).Position,
+		Health:  health.Ammount,
+		Dead:    health.Dead,
+		Summary: ms.Summary(),
+	}
+	tags.Enemy.Each(world, func(e *donburi.Entry) {
+		state.Enemies = append(state.Enemies, components.Object.Get(e).Position)
+	})
+
+	return state
+}
+
+func playerPosition(ms *MainScene) resolv.Vector {
+	return components.Object.Get(components.Player.MustFirst(ms.ECS().World)).Position
+}
+
+func killPlayer(ms *MainScene) {
+	components.Health.Get(components.Player.MustFirst(ms.ECS().World)).DamageHealth(1 << 20)
+}
+
+func TestHeadlessScene(t *testing.T) {
+	tests := []struct {
+		name   string
+		source input.Source
+		seed   int64
+		ticks  int
+		check  func(t *testing.T, ms *MainScene)
+	}{
+		{
+			name:   "same seed and script, same end state",
+			source: strafe,
+			seed:   1,
+			ticks:  300,
+			check: func(t *testing.T, ms *MainScene) {
+				again := NewHeadlessScene(strafe, 1)
+				again.Run(300)
+
+				a, b := snapshot(ms), snapshot(again)
+				if a.Tick != b.Tick || a.Player != b.Player || a.Health != b.Health || a.Dead != b.Dead || a.Summary != b.Summary {
+					t.Fatalf("runs differ:\n%+v\n%+v", a, b)
+				}
+				if len(a.Enemies) != len(b.Enemies) {
+					t.Fatalf("%d enemies, then %d", len(a.Enemies), len(b.Enemies))
+				}
+				for i := range a.Enemies {
+					if a.Enemies[i] != b.Enemies[i] {
+						t.Fatalf("enemy %d at %v, then %v", i, a.Enemies[i], b.Enemies[i])
+					}
+				}
+			},
+		},
+		{
+			name:   "strafe moves the player",
+			source: strafe,
+			seed:   1,
+			ticks:  0,
+			check: func(t *testing.T, ms *MainScene) {
+				start := playerPosition(ms)
+				ms.Run(30)
+				if end := playerPosition(ms); end == start {
+					t.Fatalf("player stayed at %v", start)
+				}
+			},
+		},
+		{
+			name:   "idle keeps the player still",
+			source: input.Idle,
+			seed:   1,
+			ticks:  0,
+			check: func(t *testing.T, ms *MainScene) {
+				start := playerPosition(ms)
+				ms.Run(30)
+				if end := playerPosition(ms); end != start {
+					t.Fatalf("player moved from %v to %v", start, end)
+				}
+			},
+		},
+		{
+			name:   "run stops on player death",
+			source: input.Idle,
+			seed:   1,
+			ticks:  10,
+			check: func(t *testing.T, ms *MainScene) {
+				killPlayer(ms)
+				ms.Run(1)
+				if !ms.GameOver() {
+					t.Fatal("no game over after the player died")
+				}
+
+				//nothing is pressed so the run never restarts
+				before := ms.Summary()
+				ms.Run(int(3 * restartDelay * float64(config.TickRate)))
+				if !ms.GameOver() || ms.Summary().Seed != before.Seed {
+					t.Fatalf("run went on after death: %+v", ms.Summary())
+				}
+			},
+		},
+		{
+			name:   "fire restarts a finished run",
+			source: input.Script(func(tick int) input.State { return input.State{Fire: tick%2 == 0} }),
+			seed:   1,
+			ticks:  10,
+			check: func(t *testing.T, ms *MainScene) {
+				killPlayer(ms)
+				ms.Run(int(2 * restartDelay * float64(config.TickRate)))
+				if ms.GameOver() {
+					t.Fatal("run did not restart")
+				}
+				if ms.Summary().Seed == 1 {
+					t.Fatal("restarted run kept the old seed")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := NewHeadlessScene(tt.source, tt.seed)
+			ms.Run(tt.ticks)
+			tt.check(t, ms)
+		})
+	}
+}
+
+func TestWorldErrors(t *testing.T) {
+	broken := utils.DefaultMapOptions()
+	broken.BarrelDensity = 2
+
+	tests := []struct {
+		name  string
+		level string
+		opts  *utils.MapOptions //generated instead of starting a scene
+	}{
+		{"missing level", "missing.json", nil},
+		{"broken options", "", &broken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.opts != nil {
+				if _, err := generateWorld(*tt.opts); err == nil {
+					t.Fatal("no error")
+				}
+				return
+			}
+
+			level := config.C.Level
+			config.C.Level = tt.level
+			defer func() { config.C.Level = level }()
+
+			m := NewManager()
+			ms := NewMainScene(m, strafe, 1)
+			m.Push(ms)
+			if ms.Err() == nil {
+				t.Fatal("no error")
+			}
+			if err := m.Update(); err != ms.Err() {
+				t.Fatalf("manager stopped with %v, want %v", err, ms.Err())
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package scenes
+
+import (
+	"image/color"
+
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+type Scene interface {
+	Update()
+	Draw(screen *ebiten.Image)
+}
+
+// ticks spent fading out and again fading in during a transition
+const fadeTicks = 15
+
+type transition struct {
+	scene Scene
+	reset bool //drop the whole stack instead of the top scene
+	tick  int
+}
+
+// Manager keeps a stack of scenes. Only the top scene is updated, every scene
+// is drawn from the bottom up so overlays like the pause menu sit on top of
+// the game they interrupt.
+type Manager struct {
+	stack      []Scene
+	transition *transition
+	quit       bool
+}
+
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Push puts a scene on top of the stack right away.
+func (m *Manager) Push(s Scene) {
+	m.stack = append(m.stack, s)
+}
+
+// Pop removes the top scene right away.
+func (m *Manager) Pop() {
+	if len(m.stack) > 0 {
+		m.stack = m.stack[:len(m.stack)-1]
+	}
+}
+
+// Replace fades out, swaps the top scene and fades back in.
+func (m *Manager) Replace(s Scene) {
+	m.transition = &transition{scene: s}
+}
+
+// Reset fades out and starts over with a single scene.
+func (m *Manager) Reset(s Scene) {
+	m.transition = &transition{scene: s, reset: true}
+}
+
+// Quit ends the game after the current update.
+func (m *Manager) Quit() {
+	m.quit = true
+}
+
+// Top returns the scene receiving updates.
+func (m *Manager) Top() Scene {
+	if len(m.stack) == 0 {
+		return nil
+	}
+	return m.stack[len(m.stack)-1]
+}
+
+func (m *Manager) Update() error {
+	if t := m.transition; t != nil {
+		t.tick++
+
+		if t.tick == fadeTicks {
+			if t.reset {
+				m.stack = m.stack[:0]
+			} else {
+				m.Pop()
+			}
+			m.Push(t.scene)
+		}
+
+		if t.tick >= fadeTicks*2 {
+			m.transition = nil
+		}
+
+		//the old scene freezes while it fades out
+		if t.tick < fadeTicks {
+			return nil
+		}
+	}
+
+	if top := m.Top(); top != nil {
+		top.Update()
+
+		//a game that could not build its floor ends with the reason
+		if s, ok := top.(interface{ Err() error }); ok && s.Err() != nil {
+			return s.Err()
+		}
+	}
+
+	if m.quit {
+		return ebiten.Termination
+	}
+
+	return nil
+}
+
+func (m *Manager) Draw(screen *ebiten.Image) {
+	for _, s := range m.stack {
+		s.Draw(screen)
+	}
+
+	if t := m.transition; t != nil {
+		alpha := float64(t.tick) / fadeTicks
+		if t.tick > fadeTicks {
+			alpha = float64(fadeTicks*2-t.tick) / fadeTicks
+		}
+
+		a := uint8(255 * alpha)
+		vector.DrawFilledRect(screen, 0, 0, float32(config.C.ScreenWidth), float32(config.C.ScreenHeight), color.RGBA{0, 0, 0, a}, false)
+	}
+}
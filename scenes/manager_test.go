@@ -0,0 +1,73 @@
+package scenes
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/AndriiPets/FishGame/input"
+	"github.com/AndriiPets/FishGame/replay"
+	"github.com/yohamta/donburi/features/math"
+)
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// menuRun starts on the title, plays, pauses and restarts from the pause
+// menu, walking right in between.
+var menuRun = input.Script(func(tick int) input.State {
+	switch tick {
+	case 2:
+		return input.State{MenuConfirm: true} //play
+	case 60:
+		return input.State{Pause: true}
+	case 62, 64:
+		return input.State{MenuDown: true}
+	case 66:
+		return input.State{MenuConfirm: true} //restart
+	}
+
+	return input.State{Move: math.NewVec2(1, 0), Fire: tick%2 == 0}
+})
+
+func TestManagerReplay(t *testing.T) {
+	const updates = 200
+
+	var buf bytes.Buffer
+	rec, err := replay.NewRecorder(nopCloser{&buf}, replay.Header{Seed: 1, Title: true}, menuRun)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(source input.Source) *MainScene {
+		m := NewManager()
+		m.Push(NewTitleScene(m, &input.Sequence{Source: source}, 1))
+		for i := 0; i < updates; i++ {
+			m.Update()
+		}
+
+		game, ok := m.Top().(*MainScene)
+		if !ok {
+			t.Fatalf("top scene is %T, want the game", m.Top())
+		}
+		return game
+	}
+
+	recorded := run(rec)
+	rec.Close()
+
+	player, err := replay.Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed := run(player)
+
+	a, b := snapshot(recorded), snapshot(replayed)
+	if a.Summary.Seed == 1 {
+		t.Fatal("the pause menu did not restart the run")
+	}
+	if a.Tick != b.Tick || a.Player != b.Player || a.Summary != b.Summary {
+		t.Fatalf("replay differs:\n%+v\n%+v", a, b)
+	}
+}
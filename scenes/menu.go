@@ -0,0 +1,72 @@
+package scenes
+
+import (
+	"image/color"
+
+	"github.com/AndriiPets/FishGame/input"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+type menuItem struct {
+	label  func() string
+	action func()
+}
+
+func item(label string, action func()) menuItem {
+	return menuItem{label: func() string { return label }, action: action}
+}
+
+// menu is a vertical list driven by the menu buttons of an input.Source, the
+// same one the game reads so replays go through menus as well.
+type menu struct {
+	title    string
+	items    []menuItem
+	selected int
+	back     func() //escape, nil when the menu can't be left
+	input    input.Source
+	previous input.State
+	polls    int
+}
+
+func (m *menu) update() {
+	in := m.input.Poll(m.polls)
+	prev := m.previous
+	m.previous = in
+	m.polls++
+
+	//buttons still held from before the menu opened, like pause, need a release
+	if m.polls == 1 {
+		return
+	}
+
+	switch {
+	case in.MenuUp && !prev.MenuUp:
+		m.selected = (m.selected - 1 + len(m.items)) % len(m.items)
+	case in.MenuDown && !prev.MenuDown:
+		m.selected = (m.selected + 1) % len(m.items)
+	case in.MenuConfirm && !prev.MenuConfirm:
+		m.items[m.selected].action()
+	case m.back != nil && in.MenuBack && !prev.MenuBack:
+		m.back()
+	}
+}
+
+func (m *menu) draw(screen *ebiten.Image, x, y int) {
+	ebitenutil.DebugPrintAt(screen, m.title, x, y)
+
+	for i, it := range m.items {
+		label := "  " + it.label()
+		if i == m.selected {
+			label = "> " + it.label()
+		}
+		ebitenutil.DebugPrintAt(screen, label, x, y+32+i*16)
+	}
+}
+
+// dim darkens whatever was drawn below an overlay.
+func dim(screen *ebiten.Image) {
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	vector.DrawFilledRect(screen, 0, 0, float32(w), float32(h), color.RGBA{0, 0, 0, 160}, false)
+}
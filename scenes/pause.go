@@ -0,0 +1,45 @@
+package scenes
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// PauseScene is pushed over a running game, the game below stops updating
+// but is still drawn.
+type PauseScene struct {
+	manager *Manager
+	menu    *menu
+}
+
+func NewPauseScene(m *Manager, game *MainScene) *PauseScene {
+	ps := &PauseScene{manager: m}
+
+	ps.menu = &menu{
+		title: "PAUSED",
+		items: []menuItem{
+			item("Resume", m.Pop),
+			item("Settings", func() {
+				m.Push(NewSettingsScene(m, game.input))
+			}),
+			item("Restart", func() {
+				m.Reset(game.Next())
+			}),
+			item("Quit to title", func() {
+				m.Reset(NewTitleScene(m, game.input, game.NextSeed()))
+			}),
+		},
+		back:  m.Pop,
+		input: game.input,
+	}
+
+	return ps
+}
+
+func (ps *PauseScene) Update() {
+	ps.menu.update()
+}
+
+func (ps *PauseScene) Draw(screen *ebiten.Image) {
+	dim(screen)
+	ps.menu.draw(screen, 48, 48)
+}
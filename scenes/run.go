@@ -0,0 +1,52 @@
+package scenes
+
+import (
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/yohamta/donburi"
+)
+
+// RunState is carried from floor to floor until the player dies.
+type RunState struct {
+	Seed      int64 //seed of the first floor
+	Depth     int   //floors cleared so far
+	StartTick int
+	Kills     int //enemies killed on the floors already left
+
+	//the player as they left the last floor, unset on the first one
+	Health    int
+	Inventory *components.InventoryData
+}
+
+func newRun(seed int64, tick int) *RunState {
+	return &RunState{Seed: seed, StartTick: tick}
+}
+
+// save keeps what the player carries to the next floor.
+func (r *RunState) save(player *donburi.Entry) {
+	inv := components.Inventory.Get(player)
+	inv.Save(components.Shooter.Get(player))
+
+	carried := *inv
+	carried.Slots = append([]components.WeaponSlot(nil), inv.Slots...)
+
+	r.Inventory = &carried
+	r.Health = components.Health.Get(player).Ammount
+}
+
+// restore hands a freshly spawned player what they carried from the last floor.
+func (r *RunState) restore(player *donburi.Entry) {
+	if r.Inventory == nil {
+		return
+	}
+
+	inv := components.Inventory.Get(player)
+	current := r.Inventory.Current
+	*inv = *r.Inventory
+	inv.Slots = append([]components.WeaponSlot(nil), r.Inventory.Slots...)
+
+	//nothing to save, the starting weapon is replaced
+	inv.Current = -1
+	inv.Equip(current, components.Shooter.Get(player))
+
+	components.Health.Get(player).Ammount = r.Health
+}
@@ -0,0 +1,57 @@
+package scenes
+
+import (
+	"image/color"
+
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/input"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type SettingsScene struct {
+	manager *Manager
+	menu    *menu
+}
+
+func NewSettingsScene(m *Manager, source input.Source) *SettingsScene {
+	ss := &SettingsScene{manager: m}
+
+	ss.menu = &menu{
+		title: "SETTINGS",
+		items: []menuItem{
+			{
+				label:  func() string { return "Screen shake: " + onOff(config.C.ScreenShake) },
+				action: func() { config.C.ScreenShake = !config.C.ScreenShake },
+			},
+			{
+				label:  func() string { return "Fullscreen: " + onOff(ebiten.IsFullscreen()) },
+				action: func() { ebiten.SetFullscreen(!ebiten.IsFullscreen()) },
+			},
+			{
+				label:  func() string { return "Debug overlay: " + onOff(config.C.Debug) },
+				action: func() { config.C.Debug = !config.C.Debug },
+			},
+			item("Back", m.Pop),
+		},
+		back:  m.Pop,
+		input: source,
+	}
+
+	return ss
+}
+
+func (ss *SettingsScene) Update() {
+	ss.menu.update()
+}
+
+func (ss *SettingsScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{20, 20, 20, 255})
+	ss.menu.draw(screen, 48, 48)
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
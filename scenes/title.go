@@ -0,0 +1,43 @@
+package scenes
+
+import (
+	"image/color"
+
+	"github.com/AndriiPets/FishGame/input"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type TitleScene struct {
+	manager *Manager
+	menu    *menu
+}
+
+func NewTitleScene(m *Manager, source input.Source, seed int64) *TitleScene {
+	ts := &TitleScene{manager: m}
+
+	ts.menu = &menu{
+		title: "GUN GAME",
+		items: []menuItem{
+			item("Play", func() {
+				m.Replace(NewMainScene(m, source, seed))
+			}),
+			item("Settings", func() {
+				m.Push(NewSettingsScene(m, source))
+			}),
+			item("Quit", m.Quit),
+		},
+		input: source,
+	}
+
+	return ts
+}
+
+func (ts *TitleScene) Update() {
+	ts.menu.update()
+}
+
+func (ts *TitleScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{194, 178, 128, 255})
+	dim(screen)
+	ts.menu.draw(screen, 48, 48)
+}
@@ -2,63 +2,147 @@ package ai
 
 import (
 	"image/color"
+	"log"
+	gomath "math"
 
 	"github.com/AndriiPets/FishGame/components"
 	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/resources"
+	"github.com/AndriiPets/FishGame/systems"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
-	"github.com/quasilyte/pathing"
 	"github.com/yohamta/donburi"
 	"github.com/yohamta/donburi/ecs"
 	"github.com/yohamta/donburi/features/math"
 	"github.com/yohamta/donburi/filter"
 )
 
+// behaviours already reported missing, so the log is not flooded every tick
+var missingTrees = map[string]bool{}
+
 func UpdateAI(ecs *ecs.ECS) {
 	query := donburi.NewQuery(filter.Contains(components.AI, components.Object, components.AttackVector, components.Health, components.Shooter))
 	playerEntity := components.Player.MustFirst(ecs.World)
 
+	//the dead player has left the collision space, there is nothing to go after
+	if components.Health.Get(playerEntity).Dead {
+		return
+	}
+
 	query.Each(ecs.World, func(e *donburi.Entry) {
 		health := components.Health.Get(e)
 
 		//if enemy is dead stop the ai
-		if !health.Dead {
-			UpdateGruntAI(ecs, e, playerEntity)
+		if health.Dead {
+			return
+		}
+
+		//behaviour trees from the data take over from the built in ai
+		stats := resources.EnemyMap[string(components.Enemy.Get(e).Type)]
+		if tree, ok := trees[stats.Behaviour]; ok {
+			run_tree(ecs, e, playerEntity, tree)
+			return
+		}
+
+		switch components.AI.Get(e).AIType {
+		case resources.AIBrawler:
+			UpdateBrawlerAI(ecs, e, playerEntity)
+		case resources.AISniper:
+			UpdateSniperAI(ecs, e, playerEntity)
+		case resources.AITurret:
+			UpdateTurretAI(ecs, e, playerEntity)
+		case resources.AISwarmer:
+			UpdateSwarmerAI(ecs, e, playerEntity)
+		default:
+			//a shooter whose tree is missing still fights back from where it stands
+			if !missingTrees[stats.Behaviour] {
+				missingTrees[stats.Behaviour] = true
+				log.Printf("ai: no behaviour tree %q for enemy %q, falling back to a turret", stats.Behaviour, stats.Name)
+			}
+			UpdateTurretAI(ecs, e, playerEntity)
 		}
 
 	})
 }
 
 func DrawDebugAi(ecs *ecs.ECS, screen *ebiten.Image) {
+	if !systems.GetOrCreateSettings(ecs).Debug {
+		return
+	}
+
 	query := donburi.NewQuery(filter.Contains(components.AI))
+	pathfinder := components.PathFinder.MustFirst(ecs.World)
+	pf := components.PathFinder.Get(pathfinder)
+	pf.DrawDebugGrid(screen)
 
 	query.Each(ecs.World, func(e *donburi.Entry) {
 		obj := components.Object.Get(e)
 		ai := components.AI.Get(e)
-		path := ai.PathCurrent
-
-		pathfinder := components.PathFinder.MustFirst(ecs.World)
-		pf := components.PathFinder.Get(pathfinder)
+		route := components.PathFollow.Get(e)
 
 		x, y := obj.Position.X, obj.Position.Y
 		cellSize := float64(config.BlockSize)
-		pos := math.NewVec2(0, 0)
-		switch path {
-		case pathing.DirRight:
-			pos = math.NewVec2(x+cellSize, y)
-		case pathing.DirLeft:
-			pos = math.NewVec2(x-cellSize, y)
-		case pathing.DirUp:
-			pos = math.NewVec2(x, y-cellSize)
-		case pathing.DirDown:
-			pos = math.NewVec2(x, y+cellSize)
-		case pathing.DirNone:
-			pos = math.NewVec2(x, y)
+
+		//red once the enemy knows about the player
+		clr := color.RGBA{225, 225, 225, 255}
+		if ai.Alerted {
+			clr = color.RGBA{225, 60, 60, 255}
+		}
+		if !components.Health.Get(e).Dead {
+			draw_vision_cone(screen, ai, obj.Position.X, obj.Position.Y, clr)
+		}
+
+		//line to the remembered player position
+		if ai.Board.HasLastSeen {
+			last := ai.Board.LastSeen
+			vector.StrokeLine(screen, float32(x), float32(y), float32(last.X), float32(last.Y), 1, color.RGBA{160, 160, 0, 160}, false)
+			vector.StrokeRect(screen, float32(last.X-4), float32(last.Y-4), 8, 8, 1, color.RGBA{225, 225, 0, 255}, false)
 		}
-		vector.StrokeCircle(screen, float32(obj.Position.X), float32(obj.Position.Y), float32(ai.VisionRadius), 1, color.RGBA{225, 225, 225, 255}, false)
-		vector.StrokeRect(screen, float32(pos.X), float32(pos.Y), float32(cellSize), float32(cellSize), 1, color.RGBA{225, 225, 225, 255}, false)
+
+		//the route still to walk
+		from := math.NewVec2(x, y)
+		for _, wp := range route.Waypoints {
+			vector.StrokeLine(screen, float32(from.X), float32(from.Y), float32(wp.X), float32(wp.Y), 1, color.RGBA{60, 225, 60, 255}, false)
+			vector.StrokeRect(screen, float32(wp.X-2), float32(wp.Y-2), 4, 4, 1, color.RGBA{60, 225, 60, 255}, false)
+			from = wp
+		}
+
 		vector.StrokeRect(screen, float32(obj.Position.X), float32(obj.Position.Y), float32(cellSize), float32(cellSize), 1, color.RGBA{225, 225, 225, 255}, false)
-		pf.DrawDebugGrid(screen)
+	})
+
+	//noise rings fade out as they expire
+	clock := systems.GetClock(ecs)
+	components.Noise.Each(ecs.World, func(e *donburi.Entry) {
+		noise := components.Noise.Get(e)
 
+		alpha := 1 - clock.Since(noise.SpawnTick)/noise.Duration
+		if alpha <= 0 {
+			return
+		}
+		a := uint8(255 * alpha)
+
+		vector.StrokeCircle(screen, float32(noise.Position.X), float32(noise.Position.Y), float32(noise.Radius), 1, color.RGBA{0, a / 2, a, a}, false)
 	})
 }
+
+// draw_vision_cone outlines what the enemy can see, a full circle when it
+// sees all around.
+func draw_vision_cone(screen *ebiten.Image, ai *components.AIData, x, y float64, clr color.Color) {
+	if ai.VisionAngle <= 0 || ai.VisionAngle >= 360 || ai.Facing.IsZero() {
+		vector.StrokeCircle(screen, float32(x), float32(y), float32(ai.VisionRadius), 1, clr, false)
+		return
+	}
+
+	const segments = 12
+	half := ai.VisionAngle / 2 * gomath.Pi / 180
+	facing := ai.Facing.Normalized()
+	edge := facing.Rotate(-half).MulScalar(ai.VisionRadius)
+
+	vector.StrokeLine(screen, float32(x), float32(y), float32(x+edge.X), float32(y+edge.Y), 1, clr, false)
+	for i := 1; i <= segments; i++ {
+		next := facing.Rotate(-half + 2*half*float64(i)/segments).MulScalar(ai.VisionRadius)
+		vector.StrokeLine(screen, float32(x+edge.X), float32(y+edge.Y), float32(x+next.X), float32(y+next.Y), 1, clr, false)
+		edge = next
+	}
+	vector.StrokeLine(screen, float32(x), float32(y), float32(x+edge.X), float32(y+edge.Y), 1, clr, false)
+}
@@ -0,0 +1,51 @@
+package ai
+
+import (
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/resources"
+	"github.com/AndriiPets/FishGame/systems"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
+
+// UpdateBrawlerAI runs at the player, dashes in once close enough and swings
+// whenever the player is in reach.
+func UpdateBrawlerAI(ecs *ecs.ECS, enemy *donburi.Entry, player *donburi.Entry) {
+	ai := components.AI.Get(enemy)
+	shooter := components.Shooter.Get(enemy)
+	stats := resources.EnemyMap[string(components.Enemy.Get(enemy).Type)]
+	clock := systems.GetClock(ecs)
+
+	playerObj := components.Object.Get(player)
+
+	stop_moving(enemy)
+
+	if ai.Dashing {
+		if clock.Since(ai.DashTick) < stats.DashTime {
+			//keep swinging through the dash
+			shooter.Fire = true
+			return
+		}
+		ai.Dashing = false
+	}
+
+	if !ai.Sees {
+		investigate(ecs, enemy, player)
+		return
+	}
+
+	dist := aim_at(enemy, playerObj)
+
+	if dist <= stats.DashRange && clock.Since(ai.DashTick) >= stats.DashCooldown {
+		ai.Dashing = true
+		ai.DashTick = clock.Tick
+		ai.DashVec = components.AttackVector.Get(enemy).Vec
+		return
+	}
+
+	if dist <= resources.WeaponMap[shooter.Type].Range && roll_attack_initiative(ecs, ai.AgressionModifier, 100) {
+		shooter.Fire = true
+	}
+
+	move_to(ecs, enemy, playerObj.Position.X, playerObj.Position.Y)
+}
@@ -2,13 +2,12 @@ package ai
 
 import (
 	"math"
-	"math/rand"
 
 	"github.com/AndriiPets/FishGame/components"
-	"github.com/AndriiPets/FishGame/utils"
-	"github.com/quasilyte/pathing"
 	"github.com/solarlune/resolv"
+	"github.com/yohamta/donburi"
 	"github.com/yohamta/donburi/ecs"
+	dmath "github.com/yohamta/donburi/features/math"
 )
 
 func in_circle(centerX, centerY, radius, x, y float64) bool {
@@ -16,27 +15,12 @@ func in_circle(centerX, centerY, radius, x, y float64) bool {
 	return square_dist <= math.Pow(radius, 2)
 }
 
-func roll_attack_initiative(modifier, limit int) bool {
-	roll := rand.Intn(limit)
+func roll_attack_initiative(ecs *ecs.ECS, modifier, limit int) bool {
+	rng := components.Random.Get(components.Random.MustFirst(ecs.World))
+	roll := rng.Intn(limit)
 	return roll <= modifier
 }
 
-// TODO: path seems to work need to build a movement system
-func atempt_build_path(ecs *ecs.ECS, startObj, endObj *resolv.Object) pathing.BuildPathResult {
-	pathfinder := components.PathFinder.MustFirst(ecs.World)
-	pf := components.PathFinder.Get(pathfinder)
-
-	steps := pf.MakePath(
-		startObj.Position.X,
-		startObj.Position.Y,
-		endObj.Position.X,
-		endObj.Position.Y,
-		utils.BFS,
-	)
-
-	return steps
-}
-
 func line_of_sight_check(ecs *ecs.ECS, startObj, endObj *resolv.Object) (int, bool) {
 	spaceEntry := components.Space.MustFirst(ecs.World)
 	space := components.Space.Get(spaceEntry)
@@ -54,7 +38,7 @@ func line_of_sight_check(ecs *ecs.ECS, startObj, endObj *resolv.Object) (int, bo
 			continue
 		}
 
-		if cell.ContainsTags("solid") {
+		if cell.ContainsTags(components.LayerWall.Tag()) {
 			return len(sightLine), false
 		}
 	}
@@ -62,3 +46,14 @@ func line_of_sight_check(ecs *ecs.ECS, startObj, endObj *resolv.Object) (int, bo
 	return len(sightLine), true
 
 }
+
+// aim_at turns the attack vector towards the target and returns the distance to it.
+func aim_at(enemy *donburi.Entry, target *resolv.Object) float64 {
+	obj := components.Object.Get(enemy)
+	attVec := components.AttackVector.Get(enemy)
+
+	toTarget := dmath.NewVec2(target.Position.X-obj.Position.X, target.Position.Y-obj.Position.Y)
+	attVec.Vec = toTarget.Normalized()
+
+	return toTarget.Magnitude()
+}
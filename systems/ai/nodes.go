@@ -0,0 +1,228 @@
+package ai
+
+import (
+	"fmt"
+
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/systems"
+	"github.com/AndriiPets/FishGame/utils"
+	"github.com/quasilyte/pathing"
+	dmath "github.com/yohamta/donburi/features/math"
+)
+
+// Leaf is a node made from a plain function.
+type Leaf func(ctx *Context) Status
+
+func (l Leaf) Tick(ctx *Context) Status {
+	return l(ctx)
+}
+
+// seconds before a patrol point that can't be reached is given up on
+const patrolTimeout = 5.0
+
+// leaves builds the leaf nodes by name, params come from the behaviour data
+var leaves = map[string]func(params map[string]float64) (Node, error){
+	"InVision":       plain(InVision),
+	"HasLineOfSight": plain(HasLineOfSight),
+	"HasLastSeen":    plain(HasLastSeen),
+	"IsAlerted":      plain(IsAlerted),
+	"CanFire":        plain(CanFire),
+	"IsReloading":    plain(IsReloading),
+	"IsEmpty":        plain(IsEmpty),
+	"CanReload":      plain(CanReload),
+	"AimAtPlayer":    plain(AimAtPlayer),
+	"Fire":           plain(Fire),
+	"Reload":         plain(Reload),
+	"FleeFromPlayer": plain(FleeFromPlayer),
+	"FollowPath":     plain(FollowPath),
+	"Investigate":    plain(Investigate),
+	"PlayerWithin": func(params map[string]float64) (Node, error) {
+		cells, ok := params["cells"]
+		if !ok || cells <= 0 {
+			return nil, fmt.Errorf("PlayerWithin needs a positive cells param")
+		}
+		return PlayerWithin(int(cells)), nil
+	},
+	"Patrol": func(params map[string]float64) (Node, error) {
+		radius, ok := params["radius"]
+		if !ok || radius < 1 {
+			return nil, fmt.Errorf("Patrol needs a radius param of at least 1")
+		}
+		return Patrol(int(radius)), nil
+	},
+}
+
+func plain(fn func(ctx *Context) Status) func(map[string]float64) (Node, error) {
+	return func(map[string]float64) (Node, error) {
+		return Leaf(fn), nil
+	}
+}
+
+func status(ok bool) Status {
+	if ok {
+		return Success
+	}
+	return Failure
+}
+
+// sight checks the line of sight to the player once per tick.
+func (ctx *Context) sight() (int, bool) {
+	if !ctx.sightDone {
+		ctx.sightLen, ctx.canSee = line_of_sight_check(ctx.ECS, components.Object.Get(ctx.Enemy), components.Object.Get(ctx.Player))
+		ctx.sightDone = true
+	}
+	return ctx.sightLen, ctx.canSee
+}
+
+// InVision succeeds while the player is inside the vision cone.
+func InVision(ctx *Context) Status {
+	return status(in_vision(ctx.AI, components.Object.Get(ctx.Enemy), components.Object.Get(ctx.Player)))
+}
+
+// HasLineOfSight succeeds when no wall is between the enemy and the player.
+func HasLineOfSight(ctx *Context) Status {
+	_, canSee := ctx.sight()
+	return status(canSee)
+}
+
+// IsAlerted succeeds while the enemy knows about the player, seen by itself or
+// told by an ally.
+func IsAlerted(ctx *Context) Status {
+	return status(ctx.AI.Alerted)
+}
+
+// HasLastSeen succeeds while there is a last known player position to check.
+func HasLastSeen(ctx *Context) Status {
+	return status(ctx.Board.HasLastSeen)
+}
+
+// PlayerWithin succeeds when the line to the player crosses at most this many cells.
+func PlayerWithin(cells int) Leaf {
+	return func(ctx *Context) Status {
+		n, _ := ctx.sight()
+		return status(n <= cells)
+	}
+}
+
+func CanFire(ctx *Context) Status {
+	shooter := components.Shooter.Get(ctx.Enemy)
+	return status(shooter.CanFire && !shooter.Reloading && !shooter.Empty())
+}
+
+func IsReloading(ctx *Context) Status {
+	return status(components.Shooter.Get(ctx.Enemy).Reloading)
+}
+
+func IsEmpty(ctx *Context) Status {
+	return status(components.Shooter.Get(ctx.Enemy).Empty())
+}
+
+func CanReload(ctx *Context) Status {
+	return status(components.Shooter.Get(ctx.Enemy).CanReload())
+}
+
+// AimAtPlayer turns the weapon towards the player.
+func AimAtPlayer(ctx *Context) Status {
+	aim_at(ctx.Enemy, components.Object.Get(ctx.Player))
+	return Success
+}
+
+// Fire pulls the trigger when the aggression roll passes.
+func Fire(ctx *Context) Status {
+	shooter := components.Shooter.Get(ctx.Enemy)
+	if !roll_attack_initiative(ctx.ECS, ctx.AI.AgressionModifier, 100) || !shooter.CanFire {
+		return Failure
+	}
+	shooter.Fire = true
+	return Success
+}
+
+func Reload(ctx *Context) Status {
+	components.Shooter.Get(ctx.Enemy).Reload = true
+	return Success
+}
+
+// FleeFromPlayer backs away from the way the route to the player leads.
+func FleeFromPlayer(ctx *Context) Status {
+	player := components.Object.Get(ctx.Player)
+	move_to(ctx.ECS, ctx.Enemy, player.Position.X, player.Position.Y)
+	stop_moving(ctx.Enemy)
+	ctx.AI.Steer = route_heading(ctx.Enemy).MulScalar(-1)
+	return Running
+}
+
+// FollowPath walks the route towards the player.
+func FollowPath(ctx *Context) Status {
+	player := components.Object.Get(ctx.Player)
+	move_to(ctx.ECS, ctx.Enemy, player.Position.X, player.Position.Y)
+	return Running
+}
+
+// Investigate walks to where the player was last seen or heard and forgets
+// about it once there, or when the spot can't be reached. Perception forgets
+// it too once the memory runs out.
+func Investigate(ctx *Context) Status {
+	if !ctx.Board.HasLastSeen {
+		return Failure
+	}
+
+	status := walk_to(ctx, ctx.Board.LastSeen)
+	if status != Running {
+		ctx.Board.HasLastSeen = false
+	}
+	return status
+}
+
+// Patrol wanders between random floor cells up to radius cells away.
+func Patrol(radius int) Leaf {
+	return func(ctx *Context) Status {
+		clock := systems.GetClock(ctx.ECS)
+
+		if !ctx.Board.HasPatrol {
+			to, ok := pick_patrol_point(ctx, radius)
+			if !ok {
+				return Failure
+			}
+			ctx.Board.PatrolTo = to
+			ctx.Board.PatrolTick = clock.Tick
+			ctx.Board.HasPatrol = true
+		}
+
+		if walk_to(ctx, ctx.Board.PatrolTo) != Running || clock.Since(ctx.Board.PatrolTick) >= patrolTimeout {
+			ctx.Board.HasPatrol = false
+		}
+		return Running
+	}
+}
+
+// walk_to follows the route towards a point, it succeeds once the enemy is
+// in the same cell and fails when there is no way there.
+func walk_to(ctx *Context, to dmath.Vec2) Status {
+	return move_to(ctx.ECS, ctx.Enemy, to.X, to.Y)
+}
+
+func pick_patrol_point(ctx *Context, radius int) (dmath.Vec2, bool) {
+	obj := components.Object.Get(ctx.Enemy)
+	pf := components.PathFinder.Get(components.PathFinder.MustFirst(ctx.ECS.World))
+	rng := components.Random.Get(components.Random.MustFirst(ctx.ECS.World))
+	cell := pf.CoordToGrid(obj.Position.X, obj.Position.Y)
+
+	for attempt := 0; attempt < 8; attempt++ {
+		to := pathing.GridCoord{
+			X: cell.X + rng.Intn(radius*2+1) - radius,
+			Y: cell.Y + rng.Intn(radius*2+1) - radius,
+		}
+		if to == cell || to.X < 0 || to.Y < 0 || to.X >= pf.Grid.NumCols() || to.Y >= pf.Grid.NumRows() {
+			continue
+		}
+		if pf.Grid.GetCellTile(to) != utils.TileFloor {
+			continue
+		}
+
+		size := float64(config.BlockSize)
+		return dmath.NewVec2(float64(to.X)*size, float64(to.Y)*size), true
+	}
+
+	return dmath.Vec2{}, false
+}
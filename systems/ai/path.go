@@ -0,0 +1,205 @@
+package ai
+
+import (
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/systems"
+	"github.com/AndriiPets/FishGame/utils"
+	"github.com/quasilyte/pathing"
+	"github.com/solarlune/resolv"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	dmath "github.com/yohamta/donburi/features/math"
+)
+
+const (
+	replanTime    = 1.0 //seconds a route is kept while its goal stays in the same cell
+	arriveRadius  = 4.0 //pixels from a waypoint that count as reaching it
+	flowLookahead = 12  //cells of the flow field turned into a route at once
+)
+
+// move_to walks the enemy along its route to a point. It succeeds once the
+// enemy stands in the goal cell and fails when there is no way there. Routes
+// to the player come from the shared flow field, any other goal is a queued
+// path request and the enemy keeps to its old route until it is answered.
+func move_to(ecs *ecs.ECS, enemy *donburi.Entry, x, y float64) Status {
+	obj := components.Object.Get(enemy)
+	route := components.PathFollow.Get(enemy)
+	pf := components.PathFinder.Get(components.PathFinder.MustFirst(ecs.World))
+	clock := systems.GetClock(ecs)
+
+	route.Active = false
+	goal := pf.CoordToGrid(x, y)
+	if pf.CoordToGrid(obj.Position.X, obj.Position.Y) == goal {
+		return Success
+	}
+
+	stale := !route.Planned || route.Goal != goal || len(route.Waypoints) == 0 || clock.Since(route.PlanTick) >= replanTime
+
+	if pf.Flow.Valid && pf.Flow.Goal == goal {
+		route.Request = nil
+		if stale {
+			plan_flow(ecs, obj, route, pf)
+			route.PlanTick = clock.Tick
+		}
+	} else {
+		if route.Request != nil && route.Request.Done {
+			if route.Request.To == goal {
+				plan_path(ecs, obj, route, route.Request)
+				route.PlanTick = clock.Tick
+				stale = false
+			}
+			route.Request = nil
+		}
+		if route.Request != nil && route.Request.Dropped {
+			route.Request = nil
+		}
+		if stale && route.Request == nil {
+			route.Request = pf.RequestPath(obj.Position.X, obj.Position.Y, x, y)
+			route.Request.Origin = path_origin(ecs.World, enemy.Entity())
+		}
+	}
+
+	//drop the waypoints already reached
+	pos := dmath.NewVec2(obj.Position.X, obj.Position.Y)
+	for len(route.Waypoints) > 0 && pos.Distance(route.Waypoints[0]) <= arriveRadius {
+		route.Waypoints = route.Waypoints[1:]
+	}
+
+	if len(route.Waypoints) == 0 {
+		route.Planned = false
+		if route.Request != nil {
+			//waiting for the path to be worked out
+			return Running
+		}
+		return Failure
+	}
+
+	route.Active = true
+	return Running
+}
+
+// path_origin reads where the enemy stands when its request is served, a
+// despawned or dead enemy drops the request.
+func path_origin(w donburi.World, enemy donburi.Entity) func() (float64, float64, bool) {
+	return func() (float64, float64, bool) {
+		if !w.Valid(enemy) {
+			return 0, 0, false
+		}
+		entry := w.Entry(enemy)
+		if entry.HasComponent(components.Health) && components.Health.Get(entry).Dead {
+			return 0, 0, false
+		}
+
+		obj := components.Object.Get(entry)
+		return obj.Position.X, obj.Position.Y, true
+	}
+}
+
+// route_heading is the unit vector towards the next waypoint, zero without one.
+func route_heading(enemy *donburi.Entry) dmath.Vec2 {
+	route := components.PathFollow.Get(enemy)
+	if len(route.Waypoints) == 0 {
+		return dmath.NewVec2(0, 0)
+	}
+
+	obj := components.Object.Get(enemy)
+	to := route.Waypoints[0].Sub(dmath.NewVec2(obj.Position.X, obj.Position.Y))
+	if to.IsZero() {
+		return to
+	}
+	return to.Normalized()
+}
+
+// stop_moving keeps the route but stands still this tick.
+func stop_moving(enemy *donburi.Entry) {
+	components.PathFollow.Get(enemy).Active = false
+}
+
+// plan_path turns the answer to a path request into waypoints and skips the
+// ones that can be cut straight to.
+func plan_path(ecs *ecs.ECS, obj *resolv.Object, route *components.PathFollowData, req *utils.PathRequest) {
+	pf := components.PathFinder.Get(components.PathFinder.MustFirst(ecs.World))
+	path := req.Result
+
+	cell := req.From
+	points := make([]dmath.Vec2, 0, path.Steps.Len())
+	for path.Steps.HasNext() {
+		cell = cell.Move(path.Steps.Next())
+		points = append(points, cell_position(pf, obj, cell))
+	}
+
+	route.Waypoints = string_pull(components.Space.Get(components.Space.MustFirst(ecs.World)), obj, points)
+	route.Goal = req.To
+	route.Planned = true
+}
+
+// plan_flow walks down the flow field for a stretch and turns it into waypoints.
+func plan_flow(ecs *ecs.ECS, obj *resolv.Object, route *components.PathFollowData, pf *utils.PathFinder) {
+	cell := pf.CoordToGrid(obj.Position.X, obj.Position.Y)
+	points := make([]dmath.Vec2, 0, flowLookahead)
+	for i := 0; i < flowLookahead; i++ {
+		next, ok := pf.Flow.Next(cell)
+		if !ok {
+			break
+		}
+		cell = next
+		points = append(points, cell_position(pf, obj, cell))
+	}
+
+	route.Waypoints = string_pull(components.Space.Get(components.Space.MustFirst(ecs.World)), obj, points)
+	route.Goal = pf.Flow.Goal
+	route.Planned = true
+}
+
+// cell_position is where the object stands centered in the cell.
+func cell_position(pf *utils.PathFinder, obj *resolv.Object, cell pathing.GridCoord) dmath.Vec2 {
+	cx, cy := pf.Grid.CoordToPos(cell)
+	return dmath.NewVec2(cx-obj.Size.X/2, cy-obj.Size.Y/2)
+}
+
+// string_pull keeps only the waypoints where the route has to turn, going
+// straight to the farthest one the object fits through from each point.
+func string_pull(space *resolv.Space, obj *resolv.Object, points []dmath.Vec2) []dmath.Vec2 {
+	pulled := make([]dmath.Vec2, 0, len(points))
+	from := dmath.NewVec2(obj.Position.X, obj.Position.Y)
+
+	for i := 0; i < len(points); {
+		next := i
+		for j := len(points) - 1; j > i; j-- {
+			if clear_sweep(space, from, points[j], obj.Size.X, obj.Size.Y) {
+				next = j
+				break
+			}
+		}
+
+		pulled = append(pulled, points[next])
+		from = points[next]
+		i = next + 1
+	}
+
+	return pulled
+}
+
+// clear_sweep reports whether a box of the given size can move in a straight
+// line between two positions without any of its corners crossing a wall.
+func clear_sweep(space *resolv.Space, from, to dmath.Vec2, w, h float64) bool {
+	corners := [4]dmath.Vec2{
+		dmath.NewVec2(0, 0),
+		dmath.NewVec2(w-1, 0),
+		dmath.NewVec2(0, h-1),
+		dmath.NewVec2(w-1, h-1),
+	}
+
+	for _, c := range corners {
+		sx, sy := space.WorldToSpace(from.X+c.X, from.Y+c.Y)
+		ex, ey := space.WorldToSpace(to.X+c.X, to.Y+c.Y)
+
+		for _, cell := range space.CellsInLine(sx, sy, ex, ey) {
+			if cell.ContainsTags(components.LayerWall.Tag()) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,182 @@
+package ai
+
+import (
+	"math"
+
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/events"
+	"github.com/AndriiPets/FishGame/factory"
+	"github.com/AndriiPets/FishGame/systems"
+	"github.com/solarlune/resolv"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	dmath "github.com/yohamta/donburi/features/math"
+	"github.com/yohamta/donburi/filter"
+)
+
+const (
+	senseRadius = 48.0  //the player is noticed this close whichever way the enemy looks
+	alertRadius = 256.0 //allies this close and in sight are told about the player
+	scanSpeed   = 0.02  //radians an idle enemy turns its head each tick
+)
+
+// UpdatePerception works out what every enemy knows about the player before
+// the ai acts on it.
+func UpdatePerception(ecs *ecs.ECS) {
+	query := donburi.NewQuery(filter.Contains(components.AI, components.Object, components.AttackVector, components.Velocity, components.Health))
+	clock := systems.GetClock(ecs)
+	playerEntity := components.Player.MustFirst(ecs.World)
+	playerObj := components.Object.Get(playerEntity)
+	playerDead := components.Health.Get(playerEntity).Dead
+	playerPos := dmath.NewVec2(playerObj.Position.X, playerObj.Position.Y)
+
+	query.Each(ecs.World, func(e *donburi.Entry) {
+		if components.Health.Get(e).Dead {
+			return
+		}
+
+		ai := components.AI.Get(e)
+		obj := components.Object.Get(e)
+		update_facing(e, ai)
+
+		wasAlerted := ai.Alerted
+		ai.Sees = false
+		if !playerDead && in_vision(ai, obj, playerObj) {
+			_, ai.Sees = line_of_sight_check(ecs, obj, playerObj)
+		}
+
+		switch {
+		case ai.Sees:
+			remember(ai, playerPos, clock.Tick)
+			ai.Alerted = true
+		case ai.Board.HasLastSeen && clock.Since(ai.Board.LastSeenTick) >= ai.Memory:
+			ai.Board.HasLastSeen = false
+		}
+		if !ai.Sees && !ai.Board.HasLastSeen {
+			ai.Alerted = false
+		}
+
+		if ai.Sees && !wasAlerted {
+			alert_allies(ecs, e, playerPos, clock.Tick)
+		}
+	})
+}
+
+// OnNoise makes enemies hostile to the source of a noise turn towards it and
+// go check it out.
+func OnNoise(ecs *ecs.ECS) func(w donburi.World, event events.Noise) {
+	return func(w donburi.World, event events.Noise) {
+		clock := systems.GetClock(ecs)
+		factory.CreateNoise(ecs, event.Position, event.Radius, clock.Tick)
+
+		if !event.Source.Valid() {
+			return
+		}
+		owner, faction := event.Source.Entity(), components.FactionOf(event.Source)
+
+		components.AI.Each(w, func(e *donburi.Entry) {
+			ai := components.AI.Get(e)
+			obj := components.Object.Get(e)
+
+			if ai.Sees || components.Health.Get(e).Dead || !components.CanDamage(owner, faction, false, e) {
+				return
+			}
+			if !in_circle(event.Position.X, event.Position.Y, event.Radius, obj.Position.X, obj.Position.Y) {
+				return
+			}
+
+			remember(ai, event.Position, clock.Tick)
+		})
+	}
+}
+
+// UpdateNoises clears the noise rings once they have faded.
+func UpdateNoises(ecs *ecs.ECS) {
+	clock := systems.GetClock(ecs)
+
+	components.Noise.Each(ecs.World, func(e *donburi.Entry) {
+		if clock.Since(components.Noise.Get(e).SpawnTick) >= components.Noise.Get(e).Duration {
+			components.Despawnable.Get(e).DespawnRequest = true
+		}
+	})
+}
+
+// in_vision reports whether the target is inside the enemy's vision cone, or
+// close enough to be noticed from behind.
+func in_vision(ai *components.AIData, obj, target *resolv.Object) bool {
+	if !in_circle(obj.Position.X, obj.Position.Y, ai.VisionRadius, target.Position.X, target.Position.Y) {
+		return false
+	}
+	if ai.VisionAngle <= 0 || ai.VisionAngle >= 360 {
+		return true
+	}
+	if in_circle(obj.Position.X, obj.Position.Y, senseRadius, target.Position.X, target.Position.Y) {
+		return true
+	}
+
+	to := dmath.NewVec2(target.Position.X-obj.Position.X, target.Position.Y-obj.Position.Y)
+	if to.IsZero() || ai.Facing.IsZero() {
+		return false
+	}
+	dir := to.Normalized()
+	cos := ai.Facing.Normalized().Dot(&dir)
+	return math.Acos(math.Max(-1, math.Min(1, cos))) <= ai.VisionAngle/2*math.Pi/180
+}
+
+// update_facing looks where the enemy aims while it sees the player, towards
+// the last known position while it remembers one, where it walks otherwise and
+// slowly looks around while standing still.
+func update_facing(e *donburi.Entry, ai *components.AIData) {
+	obj := components.Object.Get(e)
+	attVec := components.AttackVector.Get(e).Vec
+	vel := components.Velocity.Get(e).Vel
+	toLast := dmath.NewVec2(ai.Board.LastSeen.X-obj.Position.X, ai.Board.LastSeen.Y-obj.Position.Y)
+
+	switch {
+	case ai.Sees && !attVec.IsZero():
+		ai.Facing = attVec.Normalized()
+	case ai.Board.HasLastSeen && !toLast.IsZero():
+		ai.Facing = toLast.Normalized()
+	case vel.Magnitude() > 0.1:
+		ai.Facing = vel.Normalized()
+	default:
+		ai.Facing = ai.Facing.Rotate(scanSpeed)
+	}
+}
+
+// investigate walks a hand written ai towards where the player was last seen
+// or heard, it reports false when there is nothing to check.
+func investigate(ecs *ecs.ECS, enemy, player *donburi.Entry) bool {
+	ai := components.AI.Get(enemy)
+	return Investigate(&Context{ECS: ecs, Enemy: enemy, Player: player, AI: ai, Board: &ai.Board}) == Running
+}
+
+func remember(ai *components.AIData, pos dmath.Vec2, tick int) {
+	ai.Board.LastSeen = pos
+	ai.Board.LastSeenTick = tick
+	ai.Board.HasLastSeen = true
+}
+
+// alert_allies tells the enemies around that can see the alerter where the
+// player is, walls keep the news inside the room.
+func alert_allies(ecs *ecs.ECS, alerter *donburi.Entry, playerPos dmath.Vec2, tick int) {
+	obj := components.Object.Get(alerter)
+
+	components.AI.Each(ecs.World, func(e *donburi.Entry) {
+		if e.Entity() == alerter.Entity() || components.Health.Get(e).Dead {
+			return
+		}
+
+		ai := components.AI.Get(e)
+		allyObj := components.Object.Get(e)
+		if ai.Alerted || !in_circle(obj.Position.X, obj.Position.Y, alertRadius, allyObj.Position.X, allyObj.Position.Y) {
+			return
+		}
+		if _, clear := line_of_sight_check(ecs, obj, allyObj); !clear {
+			return
+		}
+
+		remember(ai, playerPos, tick)
+		ai.Alerted = true
+	})
+}
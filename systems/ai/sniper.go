@@ -0,0 +1,98 @@
+package ai
+
+import (
+	"image/color"
+
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/resources"
+	"github.com/AndriiPets/FishGame/systems"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	dmath "github.com/yohamta/donburi/features/math"
+)
+
+// share of the telegraph at the end during which the aim no longer follows the player
+const sniperAimLock = 0.25
+
+// UpdateSniperAI keeps the player at the edge of its sight and lines up every
+// shot for a while before taking it, giving the player time to get out of the way.
+func UpdateSniperAI(ecs *ecs.ECS, enemy *donburi.Entry, player *donburi.Entry) {
+	ai := components.AI.Get(enemy)
+	shooter := components.Shooter.Get(enemy)
+	stats := resources.EnemyMap[string(components.Enemy.Get(enemy).Type)]
+	clock := systems.GetClock(ecs)
+
+	playerObj := components.Object.Get(player)
+
+	stop_moving(enemy)
+	ai.Steer = dmath.NewVec2(0, 0)
+
+	if shooter.Empty() {
+		shooter.Reload = true
+	}
+
+	if ai.Aiming {
+		//losing sight of the player calls the shot off
+		if !ai.Sees || shooter.Reloading {
+			ai.Aiming = false
+			return
+		}
+
+		aimed := clock.Since(ai.AimTick)
+		if aimed < stats.Telegraph*(1-sniperAimLock) {
+			aim_at(enemy, playerObj)
+		}
+		if aimed >= stats.Telegraph {
+			shooter.Fire = true
+			ai.Aiming = false
+		}
+		return
+	}
+
+	if !ai.Sees {
+		investigate(ecs, enemy, player)
+		return
+	}
+
+	dist := aim_at(enemy, playerObj)
+
+	//back straight away while lining up the next shot, it stands still to aim
+	if dist < stats.KeepDistance {
+		ai.Steer = components.AttackVector.Get(enemy).Vec.MulScalar(-1)
+	}
+
+	ready := clock.Since(shooter.FireTime) >= resources.WeaponMap[shooter.Type].Cooldown
+	if ready && shooter.CanFire && !shooter.Reloading && roll_attack_initiative(ecs, ai.AgressionModifier, 100) {
+		ai.Aiming = true
+		ai.AimTick = clock.Tick
+	}
+}
+
+// DrawTelegraphs shows the line a sniper is about to shoot along, brighter as the shot gets closer.
+func DrawTelegraphs(ecs *ecs.ECS, screen *ebiten.Image) {
+	clock := systems.GetClock(ecs)
+
+	components.AI.Each(ecs.World, func(e *donburi.Entry) {
+		ai := components.AI.Get(e)
+		if !ai.Aiming {
+			return
+		}
+
+		shooter := components.Shooter.Get(e)
+		stats := resources.EnemyMap[string(components.Enemy.Get(e).Type)]
+		weapon := resources.WeaponMap[shooter.Type]
+
+		from := shooter.HolderPosition
+		to := from.Add(components.AttackVector.Get(e).Vec.MulScalar(weapon.Range))
+
+		progress := clock.Since(ai.AimTick) / stats.Telegraph
+		if progress > 1 {
+			progress = 1
+		}
+		a := uint8(60 + 195*progress)
+
+		vector.StrokeLine(screen, float32(from.X), float32(from.Y), float32(to.X), float32(to.Y), 1, color.RGBA{a, 0, 0, a}, false)
+	})
+}
@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/resources"
+	"github.com/AndriiPets/FishGame/tags"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	dmath "github.com/yohamta/donburi/features/math"
+)
+
+// swarmers closer than this push each other apart
+const swarmerSpacing = 14.0
+
+// UpdateSwarmerAI moves with the swarmers around it. The whole pack goes after
+// the player as soon as one of them spots them and it keeps together on the way.
+func UpdateSwarmerAI(ecs *ecs.ECS, enemy *donburi.Entry, player *donburi.Entry) {
+	ai := components.AI.Get(enemy)
+	obj := components.Object.Get(enemy)
+	shooter := components.Shooter.Get(enemy)
+	stats := resources.EnemyMap[string(components.Enemy.Get(enemy).Type)]
+
+	playerObj := components.Object.Get(player)
+
+	stop_moving(enemy)
+	ai.Steer = dmath.NewVec2(0, 0)
+
+	pos := dmath.NewVec2(obj.Position.X, obj.Position.Y)
+	canSee := ai.Sees
+	packSees := canSee
+
+	//gather the pack
+	center := pos
+	separation := dmath.NewVec2(0, 0)
+	count := 1.0
+
+	tags.Enemy.Each(ecs.World, func(other *donburi.Entry) {
+		if other == enemy || components.AI.Get(other).AIType != resources.AISwarmer || components.Health.Get(other).Dead {
+			return
+		}
+
+		o := components.Object.Get(other)
+		otherPos := dmath.NewVec2(o.Position.X, o.Position.Y)
+		away := pos.Sub(otherPos)
+		dist := away.Magnitude()
+		if dist > stats.GroupRadius {
+			return
+		}
+
+		center = center.Add(otherPos)
+		count++
+
+		if dist < swarmerSpacing && dist > 0 {
+			separation = separation.Add(away.Normalized())
+		}
+		if !packSees && components.AI.Get(other).Sees {
+			packSees = true
+		}
+	})
+
+	center = center.MulScalar(1 / count)
+	cohesion := dmath.NewVec2(0, 0)
+	if toCenter := center.Sub(pos); toCenter.Magnitude() > stats.GroupRadius/2 {
+		cohesion = toCenter.Normalized()
+	}
+
+	if !packSees {
+		//check out what was last seen or heard, drift back together otherwise
+		if investigate(ecs, enemy, player) {
+			ai.Steer = route_heading(enemy).Add(separation.MulScalar(0.8))
+			return
+		}
+		ai.Steer = cohesion.Add(separation)
+		return
+	}
+
+	dist := aim_at(enemy, playerObj)
+
+	//straight at the player when in sight, along the path otherwise
+	chase := components.AttackVector.Get(enemy).Vec
+	if !canSee {
+		move_to(ecs, enemy, playerObj.Position.X, playerObj.Position.Y)
+		chase = route_heading(enemy)
+	}
+
+	ai.Steer = chase.Add(cohesion.MulScalar(0.3)).Add(separation.MulScalar(0.8))
+
+	if canSee && dist <= resources.WeaponMap[shooter.Type].Range && roll_attack_initiative(ecs, ai.AgressionModifier, 100) {
+		shooter.Fire = true
+	}
+}
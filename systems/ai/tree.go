@@ -0,0 +1,155 @@
+package ai
+
+import (
+	"fmt"
+
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/resources"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
+
+type Status int
+
+const (
+	Success Status = iota
+	Failure
+	Running
+)
+
+// Node is a piece of a behaviour tree. Nodes keep no state of their own, what
+// an enemy has to remember goes on its blackboard, so a single tree drives
+// every enemy using it. Trees are evaluated from the root every tick.
+type Node interface {
+	Tick(ctx *Context) Status
+}
+
+// Context is what a tree sees while it runs for one enemy.
+type Context struct {
+	ECS    *ecs.ECS
+	Enemy  *donburi.Entry
+	Player *donburi.Entry
+	AI     *components.AIData
+	Board  *components.Blackboard
+
+	//worked out at most once per tick
+	sightDone bool
+	canSee    bool
+	sightLen  int
+}
+
+// Sequence runs its children in order until one does not succeed.
+type Sequence []Node
+
+func (s Sequence) Tick(ctx *Context) Status {
+	for _, n := range s {
+		if status := n.Tick(ctx); status != Success {
+			return status
+		}
+	}
+	return Success
+}
+
+// Selector runs its children in order until one does not fail.
+type Selector []Node
+
+func (s Selector) Tick(ctx *Context) Status {
+	for _, n := range s {
+		if status := n.Tick(ctx); status != Failure {
+			return status
+		}
+	}
+	return Failure
+}
+
+// Not swaps success and failure of its child.
+type Not struct{ Child Node }
+
+func (n Not) Tick(ctx *Context) Status {
+	switch n.Child.Tick(ctx) {
+	case Success:
+		return Failure
+	case Failure:
+		return Success
+	}
+	return Running
+}
+
+// Always runs its child and succeeds whatever happened.
+type Always struct{ Child Node }
+
+func (a Always) Tick(ctx *Context) Status {
+	a.Child.Tick(ctx)
+	return Success
+}
+
+// trees compiled from the behaviour data, by name
+var trees = map[string]Node{}
+
+// LoadBehaviours compiles the behaviour trees from the behaviour data.
+func LoadBehaviours() error {
+	compiled := map[string]Node{}
+
+	for name, b := range resources.BehaviourMap {
+		root, err := BuildTree(b.Root)
+		if err != nil {
+			return fmt.Errorf("behaviour %q: %w", name, err)
+		}
+		compiled[name] = root
+	}
+
+	trees = compiled
+	return nil
+}
+
+// BuildTree turns a node description into a tree.
+func BuildTree(spec resources.BehaviourNode) (Node, error) {
+	children := make([]Node, 0, len(spec.Children))
+	for _, c := range spec.Children {
+		child, err := BuildTree(c)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	switch spec.Node {
+	case "Sequence":
+		return Sequence(children), nil
+	case "Selector":
+		return Selector(children), nil
+	case "Not", "Always":
+		if len(children) != 1 {
+			return nil, fmt.Errorf("%s needs exactly one child, got %d", spec.Node, len(children))
+		}
+		if spec.Node == "Not" {
+			return Not{children[0]}, nil
+		}
+		return Always{children[0]}, nil
+	}
+
+	leaf, ok := leaves[spec.Node]
+	if !ok {
+		return nil, fmt.Errorf("unknown node %q", spec.Node)
+	}
+	if len(children) > 0 {
+		return nil, fmt.Errorf("%s can't have children", spec.Node)
+	}
+
+	return leaf(spec.Params)
+}
+
+// run_tree ticks the enemy's tree once, movement requests start over every tick.
+func run_tree(ecs *ecs.ECS, enemy, player *donburi.Entry, root Node) {
+	ai := components.AI.Get(enemy)
+	stop_moving(enemy)
+	ai.Steer.X, ai.Steer.Y = 0, 0
+
+	root.Tick(&Context{
+		ECS:    ecs,
+		Enemy:  enemy,
+		Player: player,
+		AI:     ai,
+		Board:  &ai.Board,
+	})
+}
@@ -0,0 +1,31 @@
+package ai
+
+import (
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
+
+// UpdateTurretAI tracks the player and fires while it can see them, it never moves.
+func UpdateTurretAI(ecs *ecs.ECS, enemy *donburi.Entry, player *donburi.Entry) {
+	ai := components.AI.Get(enemy)
+	shooter := components.Shooter.Get(enemy)
+
+	playerObj := components.Object.Get(player)
+
+	stop_moving(enemy)
+
+	if shooter.Empty() {
+		shooter.Reload = true
+	}
+
+	if !ai.Sees {
+		return
+	}
+
+	aim_at(enemy, playerObj)
+
+	if roll_attack_initiative(ecs, ai.AgressionModifier, 100) {
+		shooter.Fire = true
+	}
+}
@@ -43,7 +43,19 @@ func DrawAnimation(layer ecs.LayerID) func(ecs *ecs.ECS, screen *ebiten.Image) {
 				origin_offset = 0
 			}
 
-			ganim8.DrawAnime(screen, a.Animation, middleX, o.Position.Y, a.Rotation, 1, 1, origin_offset, origin_offset)
+			if a.Fade <= 0 && a.Tint == [3]float64{} {
+				ganim8.DrawAnime(screen, a.Animation, middleX, o.Position.Y, a.Rotation, 1, 1, origin_offset, origin_offset)
+				return
+			}
+
+			r, g, b := 1.0, 1.0, 1.0
+			if a.Tint != [3]float64{} {
+				r, g, b = a.Tint[0], a.Tint[1], a.Tint[2]
+			}
+
+			opts := ganim8.DrawOpts(middleX, o.Position.Y, a.Rotation, 1, 1, origin_offset, origin_offset)
+			opts.ColorM.Scale(r, g, b, 1-a.Fade)
+			ganim8.DrawAnimeWithOpts(screen, a.Animation, opts, nil)
 		})
 	}
 }
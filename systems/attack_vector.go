@@ -24,6 +24,11 @@ func UpdateAttackVector(ecs *ecs.ECS) {
 	playerAttackVec := mouseVec.Sub(playerVec)
 	playerAttackUnit := playerAttackVec.Normalized()
 
+	//explicit aim direction takes priority over the cursor
+	if aim := GetInput(ecs).Current.Aim; !aim.IsZero() {
+		playerAttackUnit = aim.Normalized()
+	}
+
 	components.AttackVector.Each(ecs.World, func(e *donburi.Entry) {
 
 		if e.HasComponent(components.Player) {
@@ -3,6 +3,7 @@ package systems
 import (
 	"image/color"
 
+	"github.com/AndriiPets/FishGame/components"
 	dresolv "github.com/AndriiPets/FishGame/resolv"
 
 	"github.com/AndriiPets/FishGame/tags"
@@ -12,6 +13,19 @@ import (
 	"github.com/yohamta/donburi/ecs"
 )
 
+// UpdateBullets despawns bullets that outlived their projectile lifetime.
+func UpdateBullets(ecs *ecs.ECS) {
+	clock := GetClock(ecs)
+
+	tags.Bullet.Each(ecs.World, func(e *donburi.Entry) {
+		bullet := components.Bullet.Get(e)
+
+		if clock.Since(bullet.SpawnTick) >= bullet.Lifetime {
+			components.Despawnable.Get(e).DespawnRequest = true
+		}
+	})
+}
+
 func DrawBullet(ecs *ecs.ECS, image *ebiten.Image) {
 	tags.Bullet.Each(ecs.World, func(e *donburi.Entry) {
 		o := dresolv.GetObject(e)
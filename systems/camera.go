@@ -18,11 +18,12 @@ var (
 	cam       *components.CameraData
 	playerPos dmath.Vec2
 	delta     float64
+	cursor    dmath.Vec2
 )
 
 func CameraUpdate(ecs *ecs.ECS) {
 
-	delta = ecs.Time.DeltaTime().Seconds()
+	delta = DeltaTime(ecs)
 
 	cameraEntity, ok := components.Camera.First(ecs.World)
 
@@ -38,8 +39,12 @@ func CameraUpdate(ecs *ecs.ECS) {
 
 	playerPos = dmath.NewVec2(playerObj.Position.X, playerObj.Position.Y)
 
+	//smooth follow is advanced here so the camera only moves once per tick
+	follow(camera)
+
 	//translate cursor position on the screen to position in the world
-	mouseX, mouseY := ScreenToWorld(ebiten.CursorPosition())
+	cursor = GetInput(ecs).Current.Cursor
+	mouseX, mouseY := ScreenToWorld(int(cursor.X), int(cursor.Y))
 
 	camera.CursorX = mouseX
 	camera.CursorY = mouseY
@@ -62,9 +67,8 @@ func CameraRender(world, screen *ebiten.Image) {
 		GeoM: WorldMatrix(cam),
 	})
 
-	//draw aim circle
-	mouseX, mouseY := ebiten.CursorPosition()
-	mx, my := float32(mouseX), float32(mouseY)
+	//draw aim circle where the input source puts the cursor, so replays show it too
+	mx, my := float32(cursor.X), float32(cursor.Y)
 
 	aimColor := color.RGBA{0, 225, 0, 225}
 
@@ -81,19 +85,6 @@ func WorldMatrix(c *components.CameraData) ebiten.GeoM {
 
 	ViewPortCenter := dmath.NewVec2(c.ViewPort.X*0.5, c.ViewPort.Y*0.5)
 
-	//camera smooth follow calculation
-	minSpeed := 20.0
-	minEffectLen := 3.0
-	fractionSpeed := 2.3
-
-	diff := playerPos.Sub(c.Position)
-	len := diff.Magnitude()
-
-	if len > minEffectLen {
-		speed := math.Max(fractionSpeed*len, minSpeed)
-		c.Position = c.Position.Add(diff.MulScalar(speed * delta / len))
-	}
-
 	m := ebiten.GeoM{}
 	m.Translate(-c.Position.X-c.Recoil.X, -c.Position.Y-c.Recoil.Y) //target
 	//m.Translate(-c.Recoil.X, -c.Recoil.Y)     //recoil
@@ -109,6 +100,21 @@ func WorldMatrix(c *components.CameraData) ebiten.GeoM {
 	return m
 }
 
+func follow(c *components.CameraData) {
+	//camera smooth follow calculation
+	minSpeed := 20.0
+	minEffectLen := 3.0
+	fractionSpeed := 2.3
+
+	diff := playerPos.Sub(c.Position)
+	len := diff.Magnitude()
+
+	if len > minEffectLen {
+		speed := math.Max(fractionSpeed*len, minSpeed)
+		c.Position = c.Position.Add(diff.MulScalar(speed * delta / len))
+	}
+}
+
 func reset() {
 	if cam.Zoom < 0 {
 		cam.Zoom += 1
@@ -117,6 +123,13 @@ func reset() {
 	if !cam.Recoil.IsZero() {
 		cam.Recoil = cam.Recoil.DivScalar(2)
 	}
+
+	if math.Abs(cam.Shake) >= 1 {
+		cam.Recoil = cam.Recoil.Add(dmath.NewVec2(cam.Shake, cam.Shake/2))
+		cam.Shake *= -0.8
+	} else {
+		cam.Shake = 0
+	}
 }
 
 func ScreenToWorld(posX, posY int) (float64, float64) {
@@ -0,0 +1,25 @@
+package systems
+
+import (
+	"math/rand"
+
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/yohamta/donburi/ecs"
+)
+
+func UpdateClock(ecs *ecs.ECS) {
+	GetClock(ecs).Tick++
+}
+
+func GetClock(ecs *ecs.ECS) *components.ClockData {
+	return components.Clock.Get(components.Clock.MustFirst(ecs.World))
+}
+
+func GetRandom(ecs *ecs.ECS) *rand.Rand {
+	return components.Random.Get(components.Random.MustFirst(ecs.World))
+}
+
+// DeltaTime returns the fixed simulation step in seconds.
+func DeltaTime(ecs *ecs.ECS) float64 {
+	return GetClock(ecs).Delta
+}
@@ -1,9 +1,7 @@
 package systems
 
 import (
-	//"fmt"
-
-	"time"
+	"math"
 
 	"github.com/AndriiPets/FishGame/components"
 	dresolv "github.com/AndriiPets/FishGame/resolv"
@@ -11,183 +9,354 @@ import (
 
 	"github.com/yohamta/donburi"
 	"github.com/yohamta/donburi/ecs"
+	dmath "github.com/yohamta/donburi/features/math"
 	"github.com/yohamta/donburi/filter"
 )
 
-func UpdateCollisions(ecs *ecs.ECS) {
-	updatePlayerCollisions(ecs)
-	updateBulletCollisions(ecs)
-	UpdateBoucerCollisions(ecs)
+const (
+	maxSweepSteps = 4    //times an object can change direction within one tick
+	sweepSkin     = 0.01 //pixels an object that slid or bounced is kept off the surface
+)
+
+// CollisionResponse is how a moving object carries on after a contact.
+type CollisionResponse int
+
+const (
+	CollisionSlide  CollisionResponse = iota //lose the motion into the surface and keep the rest
+	CollisionPass                            //move on as if nothing was there
+	CollisionBounce                          //reflect off the surface
+	CollisionStop                            //stop where it touched
+)
+
+// Contact is a moving entry running into another object during its sweep.
+type Contact struct {
+	Entry  *donburi.Entry //the entry that moves
+	Other  *donburi.Entry //the entry it ran into
+	Object *resolv.Object //the object of Other
+	Motion dmath.Vec2     //what the entry tried to move this step
+	T      float64        //fraction of the motion travelled before the contact
+	Point  dmath.Vec2     //object position at the moment of the contact
+	Normal dmath.Vec2     //surface normal of Object, zero when it started inside
+}
+
+// CollisionHook reacts to an entry on one layer running into an object on
+// another and tells how the entry carries on.
+type CollisionHook func(ecs *ecs.ECS, c *Contact) CollisionResponse
+
+type layerPair struct {
+	moving, other components.CollisionLayer
 }
 
-func updatePlayerCollisions(ecs *ecs.ECS) {
-	query := donburi.NewQuery(filter.Contains(components.CollistionPlayer, components.Object, components.Velocity, components.Health))
-	bQuery := donburi.NewQuery(filter.Contains(components.CollistionBullet))
+// responses of the layers that meet, pairs in a mask without a hook slide
+var collisionHooks = map[layerPair]CollisionHook{
+	{components.LayerPlayerProjectile, components.LayerWall}:   projectile_hits_wall,
+	{components.LayerEnemyProjectile, components.LayerWall}:    projectile_hits_wall,
+	{components.LayerPlayerProjectile, components.LayerEnemy}:  projectile_hits_actor,
+	{components.LayerPlayerProjectile, components.LayerPlayer}: projectile_hits_actor,
+	{components.LayerEnemyProjectile, components.LayerPlayer}:  projectile_hits_actor,
+	{components.LayerEnemyProjectile, components.LayerEnemy}:   projectile_hits_actor,
+	{components.LayerPlayerProjectile, components.LayerProp}:   projectile_hits_actor,
+	{components.LayerEnemyProjectile, components.LayerProp}:    projectile_hits_actor,
+	{components.LayerPlayer, components.LayerEnemyProjectile}:  actor_hits_projectile,
+	{components.LayerEnemy, components.LayerPlayerProjectile}:  actor_hits_projectile,
+	{components.LayerPlayer, components.LayerPlayerProjectile}: actor_hits_projectile,
+	{components.LayerEnemy, components.LayerEnemyProjectile}:   actor_hits_projectile,
+}
+
+// OnCollision sets the hook for entries on the moving layer that run into
+// objects on the other layer.
+func OnCollision(moving, other components.CollisionLayer, hook CollisionHook) {
+	collisionHooks[layerPair{moving, other}] = hook
+}
+
+// UpdateCollisions moves every entry with a collider along its velocity. The
+// whole path of the tick is swept, so fast objects can't skip over thin walls
+// or actors, and whatever is in the way is resolved by the hook of the pair.
+func UpdateCollisions(ecs *ecs.ECS) {
+	query := donburi.NewQuery(filter.Contains(components.Collider, components.Object, components.Velocity))
+	space := components.Space.Get(components.Space.MustFirst(ecs.World))
 
 	query.Each(ecs.World, func(e *donburi.Entry) {
+		if e.HasComponent(components.Despawnable) && components.Despawnable.Get(e).DespawnRequest {
+			return
+		}
+
+		collider := components.Collider.Get(e)
 		object := dresolv.GetObject(e)
 		velocity := components.Velocity.Get(e)
-		UnitVector := velocity.Vel.Normalized().MulScalar(velocity.Speed)
-		health := components.Health.Get(e)
-		var damage int = 0
-		//fmt.Println(velocity.Vel)
+		motion := velocity.Vel.Normalized().MulScalar(velocity.Speed)
 
-		dx := UnitVector.X
-		dy := UnitVector.Y
+		//objects that were passed through this tick
+		var passed map[*resolv.Object]bool
 
-		if col := object.Check(dx, 0); col != nil {
-			if col.HasTags("solid") {
-				dx = col.ContactWithCell(col.Cells[len(col.Cells)-1]).X
-			}
+		for step := 0; step < maxSweepSteps && !motion.IsZero(); step++ {
+			var blocking *Contact
+			var response CollisionResponse
 
-			if bullets := col.ObjectsByTags("bullet"); len(bullets) > 0 {
-				bullet := bullets[0]
-				if object.Overlaps(bullet) {
-					if !health.Dead {
-						damage = apply_colision_with_bullet(ecs, e, col, bQuery)
+			contacts := sweep(ecs, space, e, collider.Mask, motion, passed)
+			for i := range contacts {
+				c := &contacts[i]
+				response = collision_response(ecs, collider.Layer, c)
+
+				if response == CollisionPass {
+					if passed == nil {
+						passed = map[*resolv.Object]bool{}
 					}
+					passed[c.Object] = true
+					continue
 				}
-			}
 
-			//if col.HasTags("bullet") {
-			//	if !health.Dead {
-			//		damage = apply_colision_with_bullet(ecs, e, col, bQuery)
-			//	}
-			//}
-		}
+				blocking = c
+				break
+			}
 
-		object.Position.X += dx
+			//started inside, like an actor a door closed on, there is no side to
+			//slide or bounce off so it is pushed out and swept again
+			if blocking != nil && blocking.Normal.IsZero() && response != CollisionStop {
+				push_out(object, blocking.Object)
+				continue
+			}
 
-		if col := object.Check(0, dy); col != nil {
-			if col.HasTags("solid") {
-				dy = col.ContactWithCell(col.Cells[len(col.Cells)-1]).Y
+			if blocking == nil {
+				object.Position.X += motion.X
+				object.Position.Y += motion.Y
+				return
 			}
 
-			if bullets := col.ObjectsByTags("bullet"); len(bullets) > 0 {
-				bullet := bullets[0]
-				if object.Overlaps(bullet) {
-					if !health.Dead {
-						damage = apply_colision_with_bullet(ecs, e, col, bQuery)
-					}
-				}
+			object.Position.X, object.Position.Y = blocking.Point.X, blocking.Point.Y
+			rest := motion.MulScalar(1 - blocking.T)
+			normal := blocking.Normal
+
+			switch response {
+			case CollisionStop:
+				return
+			case CollisionSlide:
+				motion = rest.Sub(normal.MulScalar(rest.Dot(&normal)))
+			case CollisionBounce:
+				velocity.Vel = reflect(velocity.Vel, normal)
+				motion = reflect(rest, normal)
 			}
 
-			//if col.HasTags("bullet") {
-			//	if !health.Dead {
-			//		damage = apply_colision_with_bullet(ecs, e, col, bQuery)
-			//	}
-			//}
+			object.Position.X += normal.X * sweepSkin
+			object.Position.Y += normal.Y * sweepSkin
 		}
+	})
+}
 
-		object.Position.Y += dy
+// push_out moves the object out of other along the axis it sinks in the least.
+func push_out(object, other *resolv.Object) {
+	depths := [4]float64{
+		object.Position.X + object.Size.X - other.Position.X, //out to the left
+		other.Position.X + other.Size.X - object.Position.X,  //out to the right
+		object.Position.Y + object.Size.Y - other.Position.Y, //out over the top
+		other.Position.Y + other.Size.Y - object.Position.Y,  //out under the bottom
+	}
+	normals := [4]dmath.Vec2{
+		dmath.NewVec2(-1, 0),
+		dmath.NewVec2(1, 0),
+		dmath.NewVec2(0, -1),
+		dmath.NewVec2(0, 1),
+	}
 
-		if damage > 0 {
-			health.DamageHealth(damage)
+	least := 0
+	for i := range depths {
+		if depths[i] < depths[least] {
+			least = i
 		}
+	}
 
-	})
+	push := normals[least].MulScalar(depths[least] + sweepSkin)
+	object.Position.X += push.X
+	object.Position.Y += push.Y
+}
+
+// collision_response runs the hook of the pair, if there is one.
+func collision_response(ecs *ecs.ECS, layer components.CollisionLayer, c *Contact) CollisionResponse {
+	other := components.Collider.Get(c.Other).Layer
+	if hook, ok := collisionHooks[layerPair{layer, other}]; ok {
+		return hook(ecs, c)
+	}
+
+	return CollisionSlide
 }
 
-func check_bullet_collision(ecs *ecs.ECS, col *resolv.Collision, query *donburi.Query) *donburi.Entry {
-	var bulletID interface{}
-	var bulletEntity *donburi.Entry
+// sweep finds every object on the mask layers the entry's box touches while it
+// moves by motion, earliest first.
+func sweep(ecs *ecs.ECS, space *resolv.Space, e *donburi.Entry, mask components.CollisionLayer, motion dmath.Vec2, skip map[*resolv.Object]bool) []Contact {
+	object := dresolv.GetObject(e)
+	from := dmath.NewVec2(object.Position.X, object.Position.Y)
 
-	for _, c := range col.Objects {
-		if c.Data != nil {
-			bulletID = c.Data
+	//every cell the box passes through, CheckWorld would floor the size instead
+	sx, sy := space.WorldToSpace(math.Min(from.X, from.X+motion.X), math.Min(from.Y, from.Y+motion.Y))
+	ex, ey := space.WorldToSpace(math.Max(from.X, from.X+motion.X)+object.Size.X, math.Max(from.Y, from.Y+motion.Y)+object.Size.Y)
+
+	var contacts []Contact
+
+	for _, o := range space.CheckCells(sx, sy, ex-sx+1, ey-sy+1) {
+		if o == object || skip[o] || has_contact(contacts, o) {
+			continue
 		}
-	}
 
-	query.Each(ecs.World, func(e *donburi.Entry) {
-		if bulletID == e.Id() {
-			despawn := components.Despawnable.Get(e)
-			despawn.DespawnRequest = true
-			bulletEntity = e
+		//grow the other object by the box size and sweep the box corner through it
+		t, normal, ok := sweep_rect(from, motion, o.Position.X-object.Size.X, o.Position.Y-object.Size.Y, o.Size.X+object.Size.X, o.Size.Y+object.Size.Y)
+		if !ok {
+			continue
 		}
-	})
 
-	return bulletEntity
-}
+		other, ok := dresolv.Entry(ecs.World, o)
+		if !ok || !other.HasComponent(components.Collider) || components.Collider.Get(other).Layer&mask == 0 {
+			continue
+		}
 
-func apply_colision_with_bullet(ecs *ecs.ECS, e *donburi.Entry, col *resolv.Collision, query *donburi.Query) int {
-	velocity := components.Velocity.Get(e)
-	health := components.Health.Get(e)
+		//keep the contacts sorted, there are only ever a few
+		contacts = append(contacts, Contact{
+			Entry:  e,
+			Other:  other,
+			Object: o,
+			Motion: motion,
+			T:      t,
+			Point:  from.Add(motion.MulScalar(t)),
+			Normal: normal,
+		})
+		for i := len(contacts) - 1; i > 0 && contacts[i].T < contacts[i-1].T; i-- {
+			contacts[i], contacts[i-1] = contacts[i-1], contacts[i]
+		}
+	}
 
-	bullet := check_bullet_collision(ecs, col, query)
-	bulletVec := components.Velocity.Get(bullet).Vel.Normalized().MulScalar(5)
-	//centerY := object.Position.Y + (object.Size.Y / 2)
-	velocity.Vel = bulletVec
-	velocity.Speed = 2
-	health.Hit = true
-	health.HitTime = time.Now()
-	damage := 1
+	return contacts
+}
 
-	return damage
+// has_contact reports whether the object is already among the contacts, objects
+// spanning several cells come up once per cell.
+func has_contact(contacts []Contact, o *resolv.Object) bool {
+	for i := range contacts {
+		if contacts[i].Object == o {
+			return true
+		}
+	}
+	return false
 }
 
-func updateBulletCollisions(ecs *ecs.ECS) {
-	query := donburi.NewQuery(filter.Contains(components.CollistionBullet, components.Object, components.Velocity))
+// projectile_hits_wall damages the wall and bounces the projectile off it
+// while it has bounces left, otherwise it stops there.
+func projectile_hits_wall(ecs *ecs.ECS, c *Contact) CollisionResponse {
+	despawn := components.Despawnable.Get(c.Entry)
+	damage_block(ecs, c.Other, components.Bullet.Get(c.Entry).Damage, c.Point)
 
-	query.Each(ecs.World, func(e *donburi.Entry) {
-		object := dresolv.GetObject(e)
-		velocity := components.Velocity.Get(e)
-		UnitVector := velocity.Vel.Normalized().MulScalar(velocity.Speed)
+	if c.Normal.IsZero() {
+		despawn.DespawnRequest = true
+		return CollisionStop
+	}
 
-		despawn := components.Despawnable.Get(e)
+	bounce(components.Bullet.Get(c.Entry), despawn)
+	if despawn.DespawnRequest {
+		return CollisionStop
+	}
 
-		dx := UnitVector.X
-		dy := UnitVector.Y
+	return CollisionBounce
+}
 
-		if col := object.Check(dx, 0); col != nil {
-			if col.HasTags("solid") {
-				despawn.DespawnRequest = true
-			}
+// projectile_hits_actor stops the projectile on the first actor it can hit.
+func projectile_hits_actor(ecs *ecs.ECS, c *Contact) CollisionResponse {
+	if !hit_with_bullet(ecs, c.Other, c.Entry, c.Motion) {
+		return CollisionPass
+	}
 
-		}
+	return CollisionStop
+}
 
-		object.Position.X += dx
+// actor_hits_projectile is an actor walking into a projectile, it takes the
+// hit and keeps walking.
+func actor_hits_projectile(ecs *ecs.ECS, c *Contact) CollisionResponse {
+	hit_with_bullet(ecs, c.Entry, c.Other, components.Velocity.Get(c.Other).Vel)
+	return CollisionPass
+}
 
-		if col := object.Check(0, dy); col != nil {
-			if col.HasTags("solid") {
-				despawn.DespawnRequest = true
-			}
-		}
+// hit_with_bullet damages and pushes the actor along dir and despawns the
+// bullet. It fails if the bullet is spent or not allowed to hit the actor.
+func hit_with_bullet(ecs *ecs.ECS, actor, bullet *donburi.Entry, dir dmath.Vec2) bool {
+	data := components.Bullet.Get(bullet)
+	despawn := components.Despawnable.Get(bullet)
+	if despawn.DespawnRequest || !actor.HasComponent(components.Health) || components.Health.Get(actor).Dead || !data.CanHit(actor) {
+		return false
+	}
 
-		object.Position.Y += dy
+	apply_hit(ecs, actor, dir, data.Knockback)
+	if data.Damage > 0 {
+		components.Health.Get(actor).DamageHealth(data.Damage)
+	}
+	despawn.DespawnRequest = true
 
-	})
+	return true
 }
 
-func UpdateBoucerCollisions(ecs *ecs.ECS) {
-	query := donburi.NewQuery(filter.Contains(components.CollisionBouncer, components.Object, components.Velocity))
+// apply_hit flags the entity as hit and pushes it along dir if it can move.
+func apply_hit(ecs *ecs.ECS, e *donburi.Entry, dir dmath.Vec2, knockback float64) {
+	health := components.Health.Get(e)
 
-	query.Each(ecs.World, func(e *donburi.Entry) {
-		object := dresolv.GetObject(e)
+	if knockback > 0 && e.HasComponent(components.Velocity) {
 		velocity := components.Velocity.Get(e)
-		UnitVector := velocity.Vel.Normalized().MulScalar(velocity.Speed)
-
-		//despawn := components.Despawnable.Get(e)
+		velocity.Vel = dir.Normalized().MulScalar(5)
+		velocity.Speed = knockback
+	}
+	health.Hit = true
+	health.HitTime = GetClock(ecs).Tick
+}
 
-		dx := UnitVector.X
-		dy := UnitVector.Y
+// bounce uses up one wall bounce, despawning the bullet once it has none left.
+func bounce(bullet *components.BulletData, despawn *components.DespawnableData) {
+	bullet.Bounces--
+	if bullet.Bounces < 0 {
+		despawn.DespawnRequest = true
+	}
+}
 
-		if col := object.Check(dx, 0); col != nil {
-			if col.HasTags("solid") {
-				dx = col.ContactWithCell(col.Cells[0]).X
-				velocity.Vel.X *= -1
+// sweep_rect returns the fraction of the segment at which it enters the
+// rectangle and the normal of the side it comes through, using the slab
+// method. Segments starting inside report a zero normal, segments that only
+// touch an edge miss.
+func sweep_rect(from, ray dmath.Vec2, x, y, w, h float64) (float64, dmath.Vec2, bool) {
+	tMin, tMax := 0.0, 1.0
+	normal := dmath.NewVec2(0, 0)
+
+	for axis, slab := range [][4]float64{
+		{from.X, ray.X, x, x + w},
+		{from.Y, ray.Y, y, y + h},
+	} {
+		origin, delta, lo, hi := slab[0], slab[1], slab[2], slab[3]
+
+		if delta == 0 {
+			if origin <= lo || origin >= hi {
+				return 0, normal, false
 			}
+			continue
 		}
 
-		object.Position.X += dx
-
-		if col := object.Check(0, dy); col != nil {
-			if col.HasTags("solid") {
-				dy = col.ContactWithCell(col.Cells[0]).Y
-				velocity.Vel.Y *= -1
+		t1, t2 := (lo-origin)/delta, (hi-origin)/delta
+		side := -1.0
+		if t1 > t2 {
+			t1, t2 = t2, t1
+			side = 1
+		}
+		if t1 > tMin {
+			tMin = t1
+			normal = dmath.NewVec2(0, 0)
+			if axis == 0 {
+				normal.X = side
+			} else {
+				normal.Y = side
 			}
 		}
+		tMax = math.Min(tMax, t2)
+		if tMin >= tMax {
+			return 0, normal, false
+		}
+	}
 
-		object.Position.Y += dy
+	return tMin, normal, true
+}
 
-	})
+// reflect mirrors v on the surface with the given normal.
+func reflect(v, normal dmath.Vec2) dmath.Vec2 {
+	return v.Sub(normal.MulScalar(2 * v.Dot(&normal)))
 }
@@ -0,0 +1,310 @@
+package systems
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/AndriiPets/FishGame/archetypes"
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/factory"
+	dresolv "github.com/AndriiPets/FishGame/resolv"
+	"github.com/AndriiPets/FishGame/utils"
+	"github.com/solarlune/resolv"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	dmath "github.com/yohamta/donburi/features/math"
+)
+
+// bulletStart is where a bullet goes back to before every measured tick.
+type bulletStart struct {
+	entry *donburi.Entry
+	pos   resolv.Vector
+}
+
+// collisionWorld builds a space with the seed 1 level walls, actors on random
+// floor cells and bullets flying around them, half of them right on top of an
+// actor.
+func collisionWorld(b *testing.B, bullets, actors int) (*ecs.ECS, []bulletStart) {
+	world := utils.NewWorldMap()
+	opts := utils.DefaultMapOptions()
+	opts.Seed = 1
+	if err := world.GenerateMap(opts); err != nil {
+		b.Fatal(err)
+	}
+
+	e := ecs.NewECS(donburi.NewWorld())
+	factory.CreateClock(e, 1/float64(config.TickRate))
+	space := factory.CreateSpace(e)
+	size := float64(config.BlockSize)
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	var floor [][2]float64
+	for y, row := range world.Map.Data {
+		for x, val := range row {
+			if val != 'x' {
+				floor = append(floor, [2]float64{float64(x) * size, float64(y) * size})
+				continue
+			}
+			wall := archetypes.Wall.Spawn(e)
+			dresolv.SetObject(wall, resolv.NewObject(float64(x)*size, float64(y)*size, size, size))
+			dresolv.SetCollider(wall, components.LayerWall, 0)
+			dresolv.Add(space, wall)
+		}
+	}
+
+	targets := make([]*resolv.Object, actors)
+	for i := range targets {
+		cell := floor[rng.Intn(len(floor))]
+		actor := archetypes.Enemy.Spawn(e)
+		obj := resolv.NewObject(cell[0]+8, cell[1]+8, 16, 16)
+		dresolv.SetObject(actor, obj)
+		dresolv.SetCollider(actor, components.LayerEnemy, components.LayerWall|components.LayerPlayerProjectile)
+		dresolv.Add(space, actor)
+		components.Health.Get(actor).Ammount = 1
+		targets[i] = obj
+	}
+
+	starts := make([]bulletStart, 0, bullets)
+	for i := 0; i < bullets; i++ {
+		var x, y float64
+		if i%2 == 0 {
+			t := targets[rng.Intn(len(targets))]
+			x, y = t.Position.X+rng.Float64()*8, t.Position.Y+rng.Float64()*8
+		} else {
+			cell := floor[rng.Intn(len(floor))]
+			x, y = cell[0]+12, cell[1]+12
+		}
+
+		bullet := archetypes.Bullet.Spawn(e)
+		obj := resolv.NewObject(x, y, 8, 8)
+		dresolv.SetObject(bullet, obj)
+		dresolv.SetCollider(bullet, components.LayerPlayerProjectile, components.LayerWall|components.LayerEnemy)
+		dresolv.Add(space, bullet)
+
+		//no damage keeps the actors alive for every run
+		components.Bullet.SetValue(bullet, components.BulletData{Faction: components.FactionPlayer, Knockback: 1})
+		angle := rng.Float64() * 6.28
+		components.Velocity.SetValue(bullet, components.VelocityData{Vel: dmath.NewVec2(1, 0).Rotate(angle), Speed: 15})
+
+		starts = append(starts, bulletStart{entry: bullet, pos: obj.Position})
+	}
+
+	return e, starts
+}
+
+// resetBullets puts the bullets back where they started and calms the actors.
+func resetBullets(e *ecs.ECS, starts []bulletStart) {
+	for _, s := range starts {
+		obj := components.Object.Get(s.entry)
+		obj.Position = s.pos
+		obj.Update()
+		components.Despawnable.Get(s.entry).DespawnRequest = false
+	}
+
+	components.Health.Each(e.World, func(a *donburi.Entry) {
+		components.Health.Get(a).Hit = false
+		components.Velocity.Get(a).Speed = 0
+	})
+}
+
+func BenchmarkCollisions(b *testing.B) {
+	e, starts := collisionWorld(b, 400, 48)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		resetBullets(e, starts)
+		b.StartTimer()
+
+		UpdateCollisions(e)
+	}
+}
+
+// hookWorld is an empty space with a clock for the collision hooks to run in.
+func hookWorld() (*ecs.ECS, *donburi.Entry) {
+	e := ecs.NewECS(donburi.NewWorld())
+	factory.CreateClock(e, 1/float64(config.TickRate))
+	return e, factory.CreateSpace(e)
+}
+
+func testWall(e *ecs.ECS, space *donburi.Entry, x, y, w, h float64) *donburi.Entry {
+	wall := archetypes.Wall.Spawn(e)
+	dresolv.SetObject(wall, resolv.NewObject(x, y, w, h))
+	dresolv.SetCollider(wall, components.LayerWall, 0)
+	dresolv.Add(space, wall)
+	return wall
+}
+
+func testActor(e *ecs.ECS, space *donburi.Entry, x, y float64, vel dmath.Vec2, speed float64) *donburi.Entry {
+	actor := archetypes.Enemy.Spawn(e)
+	dresolv.SetObject(actor, resolv.NewObject(x, y, 16, 16))
+	dresolv.SetCollider(actor, components.LayerEnemy, components.LayerWall|components.LayerPlayerProjectile|components.LayerEnemyProjectile)
+	dresolv.Add(space, actor)
+	components.Health.Get(actor).Ammount = 10
+	components.Velocity.SetValue(actor, components.VelocityData{Vel: vel, Speed: speed})
+	return actor
+}
+
+func testBullet(e *ecs.ECS, space *donburi.Entry, x, y float64, vel dmath.Vec2, speed float64, data components.BulletData) *donburi.Entry {
+	bullet := archetypes.Bullet.Spawn(e)
+	dresolv.SetObject(bullet, resolv.NewObject(x, y, 8, 8))
+	collider := components.ProjectileCollider(data.Faction, data.FriendlyFire)
+	dresolv.SetCollider(bullet, collider.Layer, collider.Mask)
+	dresolv.Add(space, bullet)
+	components.Bullet.SetValue(bullet, data)
+	components.Velocity.SetValue(bullet, components.VelocityData{Vel: vel, Speed: speed})
+	return bullet
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 0.05
+}
+
+func TestCollisionHooks(t *testing.T) {
+	up, down, right := dmath.NewVec2(0, -1), dmath.NewVec2(0, 1), dmath.NewVec2(1, 0)
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T, e *ecs.ECS, space *donburi.Entry)
+	}{
+		{"actor slides along a wall", func(t *testing.T, e *ecs.ECS, space *donburi.Entry) {
+			testWall(e, space, 0, 0, 320, 32)
+			actor := testActor(e, space, 100, 35, dmath.NewVec2(1, -1), 10)
+			UpdateCollisions(e)
+
+			obj := dresolv.GetObject(actor)
+			if !near(obj.Position.Y, 32) || !near(obj.Position.X, 100+10/math.Sqrt2) {
+				t.Fatalf("actor at %v, want it against the wall and moved on along it", obj.Position)
+			}
+		}},
+		{"bullet bounces off a wall", func(t *testing.T, e *ecs.ECS, space *donburi.Entry) {
+			testWall(e, space, 0, 0, 320, 32)
+			bullet := testBullet(e, space, 100, 40, up, 10, components.BulletData{Faction: components.FactionPlayer, Bounces: 1})
+			UpdateCollisions(e)
+
+			obj := dresolv.GetObject(bullet)
+			vel := components.Velocity.Get(bullet).Vel
+			if !near(obj.Position.Y, 34) || vel != down {
+				t.Fatalf("bullet at %v heading %v, want it back at 34 heading down", obj.Position, vel)
+			}
+			if components.Bullet.Get(bullet).Bounces != 0 || components.Despawnable.Get(bullet).DespawnRequest {
+				t.Fatal("the bounce was not used up")
+			}
+		}},
+		{"bullet stops on the actor it hits", func(t *testing.T, e *ecs.ECS, space *donburi.Entry) {
+			actor := testActor(e, space, 120, 100, dmath.Vec2{}, 0)
+			bullet := testBullet(e, space, 100, 104, right, 30, components.BulletData{Faction: components.FactionPlayer, Damage: 1})
+			UpdateCollisions(e)
+
+			if x := dresolv.GetObject(bullet).Position.X; !near(x, 112) {
+				t.Fatalf("bullet at x %.2f, want it stopped at 112", x)
+			}
+			health := components.Health.Get(actor)
+			if !health.Hit || health.Ammount != 9 || !components.Despawnable.Get(bullet).DespawnRequest {
+				t.Fatalf("hit %t health %d, want a hit for 1 and the bullet gone", health.Hit, health.Ammount)
+			}
+		}},
+		{"actor passes a spent bullet", func(t *testing.T, e *ecs.ECS, space *donburi.Entry) {
+			bullet := testBullet(e, space, 124, 104, dmath.Vec2{}, 0, components.BulletData{Faction: components.FactionPlayer, Damage: 1})
+			components.Despawnable.Get(bullet).DespawnRequest = true
+			actor := testActor(e, space, 100, 100, right, 40)
+			UpdateCollisions(e)
+
+			if x := dresolv.GetObject(actor).Position.X; !near(x, 140) {
+				t.Fatalf("actor at x %.2f, want it through the bullet at 140", x)
+			}
+			if components.Health.Get(actor).Hit {
+				t.Fatal("spent bullet hit the actor")
+			}
+		}},
+		{"actor walks into a friendly fire bullet", func(t *testing.T, e *ecs.ECS, space *donburi.Entry) {
+			testBullet(e, space, 124, 104, dmath.Vec2{}, 0, components.BulletData{Faction: components.FactionEnemy, Damage: 1, FriendlyFire: true})
+			ally := testBullet(e, space, 124, 204, dmath.Vec2{}, 0, components.BulletData{Faction: components.FactionEnemy, Damage: 1})
+			hit := testActor(e, space, 100, 100, right, 40)
+			missed := testActor(e, space, 100, 200, right, 40)
+			UpdateCollisions(e)
+
+			if !components.Health.Get(hit).Hit {
+				t.Fatal("friendly fire bullet did not hit the enemy walking into it")
+			}
+			if components.Health.Get(missed).Hit || components.Despawnable.Get(ally).DespawnRequest {
+				t.Fatal("bullet without friendly fire hit an ally")
+			}
+		}},
+		{"actor starting inside a wall is pushed out", func(t *testing.T, e *ecs.ECS, space *donburi.Entry) {
+			testWall(e, space, 64, 64, 32, 64)
+			actor := testActor(e, space, 92, 80, down, 2)
+			UpdateCollisions(e)
+
+			obj := dresolv.GetObject(actor)
+			if obj.Position.X < 96 || !near(obj.Position.Y, 82) {
+				t.Fatalf("actor at %v, want it out on the right of the wall at x 96", obj.Position)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, space := hookWorld()
+			tt.run(t, e, space)
+		})
+	}
+}
+
+func TestSweepRect(t *testing.T) {
+	//the rectangle spans 10 to 20 on both axes
+	tests := []struct {
+		name   string
+		from   dmath.Vec2
+		ray    dmath.Vec2
+		hit    bool
+		t      float64
+		normal dmath.Vec2
+	}{
+		{"from the left", dmath.NewVec2(0, 15), dmath.NewVec2(20, 0), true, 0.5, dmath.NewVec2(-1, 0)},
+		{"from the right", dmath.NewVec2(30, 15), dmath.NewVec2(-20, 0), true, 0.5, dmath.NewVec2(1, 0)},
+		{"from above", dmath.NewVec2(15, 0), dmath.NewVec2(0, 20), true, 0.5, dmath.NewVec2(0, -1)},
+		{"from below at an angle", dmath.NewVec2(10, 30), dmath.NewVec2(10, -20), true, 0.5, dmath.NewVec2(0, 1)},
+		{"too short", dmath.NewVec2(0, 15), dmath.NewVec2(5, 0), false, 0, dmath.Vec2{}},
+		{"ends on the edge", dmath.NewVec2(0, 15), dmath.NewVec2(10, 0), false, 0, dmath.Vec2{}},
+		{"runs along the edge", dmath.NewVec2(0, 10), dmath.NewVec2(30, 0), false, 0, dmath.Vec2{}},
+		{"misses", dmath.NewVec2(0, 25), dmath.NewVec2(30, 0), false, 0, dmath.Vec2{}},
+		{"starts inside", dmath.NewVec2(15, 15), dmath.NewVec2(20, 0), true, 0, dmath.Vec2{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, normal, ok := sweep_rect(tt.from, tt.ray, 10, 10, 10, 10)
+			if ok != tt.hit {
+				t.Fatalf("hit %t, want %t", ok, tt.hit)
+			}
+			if ok && (!near(got, tt.t) || normal != tt.normal) {
+				t.Fatalf("hit at %.2f through %v, want %.2f through %v", got, normal, tt.t, tt.normal)
+			}
+		})
+	}
+}
+
+func TestReflect(t *testing.T) {
+	tests := []struct {
+		name   string
+		v      dmath.Vec2
+		normal dmath.Vec2
+		want   dmath.Vec2
+	}{
+		{"head on", dmath.NewVec2(1, 0), dmath.NewVec2(-1, 0), dmath.NewVec2(-1, 0)},
+		{"off a floor", dmath.NewVec2(1, 1), dmath.NewVec2(0, -1), dmath.NewVec2(1, -1)},
+		{"off a side", dmath.NewVec2(-2, 1), dmath.NewVec2(1, 0), dmath.NewVec2(2, 1)},
+		{"along the surface", dmath.NewVec2(0, 1), dmath.NewVec2(1, 0), dmath.NewVec2(0, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reflect(tt.v, tt.normal); !near(got.X, tt.want.X) || !near(got.Y, tt.want.Y) {
+				t.Fatalf("reflected to %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package systems
+
+import (
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/events"
+	dresolv "github.com/AndriiPets/FishGame/resolv"
+	"github.com/AndriiPets/FishGame/resources"
+	"github.com/AndriiPets/FishGame/tags"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	"github.com/yohamta/donburi/features/hierarchy"
+	"github.com/yohamta/donburi/filter"
+)
+
+// seconds a corpse takes to fade out before it despawns
+const corpseFadeTime = 1.0
+
+// OnDeath turns a dead actor into a corpse: it leaves the collision space,
+// loses its weapon sprite and drops its weapon if it is an enemy.
+func OnDeath(ecs *ecs.ECS) func(w donburi.World, event events.Death) {
+	return func(w donburi.World, event events.Death) {
+		e := event.Entry
+		//blocks crumble and explosives go off instead, see OnBlockDestroyed and UpdateExplosions
+		if !e.Valid() || e.HasComponent(components.Corpse) || e.HasComponent(components.Block) || e.HasComponent(components.Explosive) {
+			return
+		}
+
+		if e.HasComponent(components.Enemy) && !resources.EnemyMap[string(components.Enemy.Get(e).Type)].NoDrop {
+			//leave the weapon behind with whatever was left in the magazine
+			shooter := components.Shooter.Get(e)
+			dropWeapon(ecs, e, components.WeaponSlot{Type: shooter.Type, Ammo: shooter.Ammo})
+		}
+
+		if children, ok := hierarchy.GetChildren(e); ok {
+			for _, c := range children {
+				if c.Valid() && c.HasComponent(tags.WeaponSprite) {
+					c.Remove()
+				}
+			}
+		}
+
+		//corpses are not solid and can't be hit anymore
+		if e.HasComponent(components.Object) {
+			dresolv.Remove(components.Space.MustFirst(w), e)
+		}
+		if e.HasComponent(components.Collider) {
+			e.RemoveComponent(components.Collider)
+		}
+
+		e.AddComponent(components.Corpse)
+		components.Corpse.SetValue(e, components.CorpseData{
+			DeathTick: GetClock(ecs).Tick,
+			Persist:   e.HasComponent(components.Player),
+		})
+	}
+}
+
+// UpdateCorpses fades corpses out and despawns them after config.C.CorpseTime.
+func UpdateCorpses(ecs *ecs.ECS) {
+	query := donburi.NewQuery(filter.Contains(components.Corpse, components.Animation))
+	clock := GetClock(ecs)
+
+	query.Each(ecs.World, func(e *donburi.Entry) {
+		corpse := components.Corpse.Get(e)
+		if corpse.Persist {
+			return
+		}
+
+		age := clock.Since(corpse.DeathTick)
+		fadeStart := config.C.CorpseTime - corpseFadeTime
+
+		if age > fadeStart {
+			components.Animation.Get(e).Fade = (age - fadeStart) / corpseFadeTime
+		}
+
+		if age >= config.C.CorpseTime && e.HasComponent(components.Despawnable) {
+			components.Despawnable.Get(e).DespawnRequest = true
+		}
+	})
+}
@@ -1,8 +1,12 @@
 package systems
 
 import (
+	"image/color"
+
 	"github.com/AndriiPets/FishGame/components"
 	"github.com/AndriiPets/FishGame/tags"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/yohamta/donburi"
 	"github.com/yohamta/donburi/ecs"
 	"github.com/yohamta/donburi/filter"
@@ -20,3 +24,32 @@ func UpdateParticles(ecs *ecs.ECS) {
 		}
 	})
 }
+
+func UpdateTracers(ecs *ecs.ECS) {
+	clock := GetClock(ecs)
+
+	components.Tracer.Each(ecs.World, func(e *donburi.Entry) {
+		tracer := components.Tracer.Get(e)
+
+		if clock.Since(tracer.SpawnTick) >= tracer.Duration {
+			components.Despawnable.Get(e).DespawnRequest = true
+		}
+	})
+}
+
+func DrawTracers(ecs *ecs.ECS, screen *ebiten.Image) {
+	clock := GetClock(ecs)
+
+	components.Tracer.Each(ecs.World, func(e *donburi.Entry) {
+		tracer := components.Tracer.Get(e)
+
+		//fade out over the tracer lifetime
+		alpha := 1 - clock.Since(tracer.SpawnTick)/tracer.Duration
+		if alpha <= 0 {
+			return
+		}
+		a := uint8(255 * alpha)
+
+		vector.StrokeLine(screen, float32(tracer.From.X), float32(tracer.From.Y), float32(tracer.To.X), float32(tracer.To.Y), 2, color.RGBA{a, a, a, a}, false)
+	})
+}
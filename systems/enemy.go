@@ -6,10 +6,10 @@ import (
 	mmath "math"
 
 	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/resources"
 	"github.com/AndriiPets/FishGame/tags"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
-	"github.com/quasilyte/pathing"
 	"github.com/yohamta/donburi"
 	"github.com/yohamta/donburi/ecs"
 	"github.com/yohamta/donburi/features/math"
@@ -29,31 +29,32 @@ func UpdateEnemies(ecs *ecs.ECS) {
 		weapon := components.Shooter.Get(e)
 		attVec := components.AttackVector.Get(e).Vec
 		ai := components.AI.Get(e)
+		route := components.PathFollow.Get(e)
+		stats := resources.EnemyMap[string(enemy.Type)]
 
 		//MOVEMENT
 
 		friction := 0.9
-		accel := 0.2
-		maxSpeed := 2.0
-
-		//move enemy according to pathfinding directions
-		if !health.Hit && !health.Dead {
-			switch ai.PathCurrent {
-			case pathing.DirRight:
-				enemyVelocity.Vel = math.NewVec2(1, 0)
-				enemyVelocity.Speed += accel
-			case pathing.DirLeft:
-				enemyVelocity.Vel = math.NewVec2(-1, 0)
+		accel := stats.Accel
+		maxSpeed := stats.Speed
+
+		//move enemy towards the next waypoint of its route, or the way the ai steers it
+		switch {
+		case health.Hit || health.Dead:
+		case ai.Dashing:
+			enemyVelocity.Vel = ai.DashVec
+			enemyVelocity.Speed = stats.DashSpeed
+		case !ai.Steer.IsZero():
+			enemyVelocity.Vel = ai.Steer.Normalized()
+			enemyVelocity.Speed += accel
+		case route.Active && len(route.Waypoints) > 0:
+			to := route.Waypoints[0].Sub(math.NewVec2(obj.Position.X, obj.Position.Y))
+			if !to.IsZero() {
+				enemyVelocity.Vel = to.Normalized()
 				enemyVelocity.Speed += accel
-			case pathing.DirUp:
-				enemyVelocity.Vel = math.NewVec2(0, -1)
-				enemyVelocity.Speed += accel
-			case pathing.DirDown:
-				enemyVelocity.Vel = math.NewVec2(0, 1)
-				enemyVelocity.Speed += accel
-			case pathing.DirNone:
-				enemyVelocity.Vel = math.NewVec2(0, 0)
 			}
+		default:
+			enemyVelocity.Vel = math.NewVec2(0, 0)
 		}
 
 		// Apply friction and horizontal speed limiting.
@@ -63,7 +64,7 @@ func UpdateEnemies(ecs *ecs.ECS) {
 			enemyVelocity.Vel = math.NewVec2(0, 0)
 		}
 
-		if enemyVelocity.Speed > maxSpeed {
+		if !ai.Dashing && enemyVelocity.Speed > maxSpeed {
 			enemyVelocity.Speed = maxSpeed
 		}
 		//
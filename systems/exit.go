@@ -0,0 +1,45 @@
+package systems
+
+import (
+	"image/color"
+
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/events"
+	dresolv "github.com/AndriiPets/FishGame/resolv"
+	"github.com/AndriiPets/FishGame/tags"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
+
+// OnExitTrigger publishes a floor exit once the living player steps on the stairs.
+func OnExitTrigger(ecs *ecs.ECS) func(w donburi.World, event events.Trigger) {
+	return func(w donburi.World, event events.Trigger) {
+		if _, ok := trigger_of(event, components.TriggerExit); !ok {
+			return
+		}
+
+		player := event.Entry
+		if !player.HasComponent(components.Player) || components.Health.Get(player).Dead {
+			return
+		}
+
+		events.FloorExitEvent.Publish(w, events.FloorExit{Entry: player})
+	}
+}
+
+func DrawExits(ecs *ecs.ECS, screen *ebiten.Image) {
+	tags.Exit.Each(ecs.World, func(e *donburi.Entry) {
+		o := dresolv.GetObject(e)
+		x, y, w, h := float32(o.Position.X), float32(o.Position.Y), float32(o.Size.X), float32(o.Size.Y)
+
+		//a dark hole with a few steps going down
+		vector.DrawFilledRect(screen, x, y, w, h, color.RGBA{30, 24, 20, 255}, false)
+		for i := float32(1); i < 4; i++ {
+			step := h / 4 * i
+			vector.StrokeLine(screen, x+step/2, y+step, x+w-step/2, y+step, 1, color.RGBA{120, 100, 80, 255}, false)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package systems
+
+import (
+	"math"
+
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/events"
+	"github.com/AndriiPets/FishGame/factory"
+	dresolv "github.com/AndriiPets/FishGame/resolv"
+	"github.com/solarlune/resolv"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	dmath "github.com/yohamta/donburi/features/math"
+	"github.com/yohamta/donburi/filter"
+)
+
+// UpdateExplosions sets off explosives that were destroyed or are about to
+// despawn, like a barrel shot to pieces or a grenade that ran out of time.
+// Explosives caught in a blast go off in turn, so they chain across the room.
+func UpdateExplosions(ecs *ecs.ECS) {
+	query := donburi.NewQuery(filter.Contains(components.Explosive, components.Object))
+
+	query.Each(ecs.World, func(e *donburi.Entry) {
+		explosive := components.Explosive.Get(e)
+		if explosive.Exploded {
+			return
+		}
+
+		dead := e.HasComponent(components.Health) && components.Health.Get(e).Dead
+		despawning := e.HasComponent(components.Despawnable) && components.Despawnable.Get(e).DespawnRequest
+		if !dead && !despawning {
+			return
+		}
+
+		explosive.Exploded = true
+		o := dresolv.GetObject(e)
+		at := dmath.NewVec2(o.Position.X+o.Size.X/2, o.Position.Y+o.Size.Y/2)
+		explode(ecs, e, at, explosive)
+
+		//a barrel's cell opens up for pathing once it is gone
+		if e.HasComponent(components.Collider) && components.Collider.Get(e).Layer == components.LayerProp {
+			pf := components.PathFinder.Get(components.PathFinder.MustFirst(ecs.World))
+			pf.SetWalkable(pf.CoordToGrid(at.X, at.Y), true)
+		}
+
+		if e.HasComponent(components.Despawnable) {
+			components.Despawnable.Get(e).DespawnRequest = true
+		}
+	})
+}
+
+// explode damages and pushes away everything with health inside the blast
+// radius. The closer an object is to the center the harder it is hit, walls
+// are only worn down and shield whatever is behind them.
+func explode(ecs *ecs.ECS, source *donburi.Entry, at dmath.Vec2, blast *components.ExplosiveData) {
+	space := components.Space.Get(components.Space.MustFirst(ecs.World))
+
+	sx, sy := space.WorldToSpace(at.X-blast.Radius, at.Y-blast.Radius)
+	ex, ey := space.WorldToSpace(at.X+blast.Radius, at.Y+blast.Radius)
+	objects := space.CheckCells(sx, sy, ex-sx+1, ey-sy+1)
+
+	var walls []*resolv.Object
+	for _, o := range objects {
+		if o.HasTags(components.LayerWall.Tag()) {
+			walls = append(walls, o)
+		}
+	}
+
+	var hit []donburi.Entity
+	for _, o := range objects {
+		e, ok := dresolv.Entry(ecs.World, o)
+		if !ok || e.Entity() == source.Entity() || !e.HasComponent(components.Health) || components.Health.Get(e).Dead || has_entity(hit, e.Entity()) {
+			continue
+		}
+
+		//distance to the nearest point of the object
+		nearest := dmath.NewVec2(
+			math.Max(o.Position.X, math.Min(at.X, o.Position.X+o.Size.X)),
+			math.Max(o.Position.Y, math.Min(at.Y, o.Position.Y+o.Size.Y)),
+		)
+		falloff := 1 - nearest.Sub(at).Magnitude()/blast.Radius
+		if falloff <= 0 || blast_blocked(walls, o, at, nearest) {
+			continue
+		}
+		hit = append(hit, e.Entity())
+
+		damage := int(math.Ceil(float64(blast.Damage) * falloff))
+		if e.HasComponent(components.Block) {
+			damage_block(ecs, e, damage, nearest)
+			continue
+		}
+
+		dir := dmath.NewVec2(o.Position.X+o.Size.X/2, o.Position.Y+o.Size.Y/2).Sub(at)
+		if dir.IsZero() {
+			dir = dmath.NewVec2(0, 1)
+		}
+		apply_hit(ecs, e, dir, blast.Knockback*falloff)
+		if damage > 0 {
+			components.Health.Get(e).DamageHealth(damage)
+		}
+	}
+
+	//one fireball in the middle and a few more the bigger the blast is
+	rng := GetRandom(ecs)
+	factory.CreateParticle(ecs, at.X, at.Y, factory.ParticleExplosion, 0, false, false)
+	for i := 1; i < int(blast.Radius/32); i++ {
+		angle := rng.Float64() * 2 * math.Pi
+		dist := rng.Float64() * blast.Radius / 2
+		factory.CreateParticle(ecs, at.X+math.Cos(angle)*dist, at.Y+math.Sin(angle)*dist, factory.ParticleExplosion, 0, false, false)
+	}
+
+	events.ScreenShakeEvent.Publish(ecs.World, events.ScreenShake{Type: "explosion"})
+}
+
+// blast_blocked reports whether a wall other than the target stands between
+// the center of a blast and the target.
+func blast_blocked(walls []*resolv.Object, target *resolv.Object, from, to dmath.Vec2) bool {
+	ray := to.Sub(from)
+	for _, w := range walls {
+		if w == target {
+			continue
+		}
+		if _, _, ok := sweep_rect(from, ray, w.Position.X, w.Position.Y, w.Size.X, w.Size.Y); ok {
+			return true
+		}
+	}
+	return false
+}
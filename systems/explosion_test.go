@@ -0,0 +1,204 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/AndriiPets/FishGame/assets"
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/factory"
+	dresolv "github.com/AndriiPets/FishGame/resolv"
+	"github.com/AndriiPets/FishGame/utils"
+	"github.com/solarlune/resolv"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	dmath "github.com/yohamta/donburi/features/math"
+)
+
+// blastLayout is an open floor for blasts that take barrels out of the path grid.
+var blastLayout = []string{
+	"          ",
+	"          ",
+	"          ",
+	"          ",
+	"          ",
+	"          ",
+	"          ",
+	"          ",
+}
+
+// blastWorld is an empty space an explosion can go off in.
+func blastWorld(t *testing.T) (*ecs.ECS, *donburi.Entry) {
+	if err := assets.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	e := ecs.NewECS(donburi.NewWorld())
+	factory.CreateClock(e, 1/float64(config.TickRate))
+	factory.CreateRandom(e, 1)
+	testPathFinder(e, blastLayout)
+	return e, factory.CreateSpace(e)
+}
+
+// center is the middle of the entry's object.
+func center(e *donburi.Entry) dmath.Vec2 {
+	o := dresolv.GetObject(e)
+	return dmath.NewVec2(o.Position.X+o.Size.X/2, o.Position.Y+o.Size.Y/2)
+}
+
+func TestExplosionWalls(t *testing.T) {
+	blast := components.ExplosiveData{Radius: 96, Damage: 4, Knockback: 8}
+
+	tests := []struct {
+		name    string
+		wall    bool //a wall stands between the blast and the actor
+		damaged bool
+	}{
+		{"in the open", false, true},
+		{"behind a wall", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, space := blastWorld(t)
+			source := factory.CreateBarrel(e, 96, 96)
+			dresolv.Add(space, source)
+			if tt.wall {
+				testWall(e, space, 160, 64, 32, 96)
+			}
+			actor := testActor(e, space, 200, 104, dmath.Vec2{}, 0)
+
+			blast := blast
+			explode(e, source, center(source), &blast)
+
+			if got := components.Health.Get(actor).Ammount < 10; got != tt.damaged {
+				t.Fatalf("actor damaged %t, want %t", got, tt.damaged)
+			}
+		})
+	}
+}
+
+func TestBarrelPathing(t *testing.T) {
+	e, space := blastWorld(t)
+	barrel := factory.CreateBarrel(e, 96, 96)
+	dresolv.Add(space, barrel)
+
+	pf := components.PathFinder.Get(components.PathFinder.MustFirst(e.World))
+	c := pf.CoordToGrid(center(barrel).X, center(barrel).Y)
+	pf.SetWalkable(c, false)
+
+	components.Health.Get(barrel).DamageHealth(2)
+	UpdateExplosions(e)
+	if pf.Grid.GetCellTile(c) != utils.TileFloor {
+		t.Fatalf("%v still closed after the barrel blew up", c)
+	}
+}
+
+func TestExplosionFalloff(t *testing.T) {
+	//the barrel at 96, 96 goes off at 112, 112
+	tests := []struct {
+		name   string
+		x, y   float64
+		damage int
+		speed  float64 //knockback, 0 when out of reach
+		dir    dmath.Vec2
+	}{
+		{"on top of it", 104, 104, 4, 8, dmath.NewVec2(0, 1)},
+		{"left", 40, 104, 2, 8 * (1 - 56.0/96), dmath.NewVec2(-1, 0)},
+		{"above", 104, 40, 2, 8 * (1 - 56.0/96), dmath.NewVec2(0, -1)},
+		{"at the edge", 202, 104, 1, 8 * (1 - 90.0/96), dmath.NewVec2(1, 0)},
+		{"out of reach", 220, 104, 0, 0, dmath.Vec2{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, space := blastWorld(t)
+			source := factory.CreateBarrel(e, 96, 96)
+			dresolv.Add(space, source)
+			actor := testActor(e, space, tt.x, tt.y, dmath.Vec2{}, 0)
+
+			explode(e, source, center(source), &components.ExplosiveData{Radius: 96, Damage: 4, Knockback: 8})
+
+			if got := 10 - components.Health.Get(actor).Ammount; got != tt.damage {
+				t.Errorf("damage %d, want %d", got, tt.damage)
+			}
+			velocity := components.Velocity.Get(actor)
+			if !near(velocity.Speed, tt.speed) {
+				t.Errorf("knockback %.2f, want %.2f", velocity.Speed, tt.speed)
+			}
+			if got := velocity.Vel.Normalized(); tt.speed > 0 && (!near(got.X, tt.dir.X) || !near(got.Y, tt.dir.Y)) {
+				t.Errorf("pushed towards %v, want %v", got, tt.dir)
+			}
+		})
+	}
+}
+
+func TestExplosives(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, e *ecs.ECS, space *donburi.Entry)
+	}{
+		{"barrels set each other off", func(t *testing.T, e *ecs.ECS, space *donburi.Entry) {
+			first := factory.CreateBarrel(e, 96, 96)
+			second := factory.CreateBarrel(e, 160, 96)
+			dresolv.Add(space, first)
+			dresolv.Add(space, second)
+
+			components.Health.Get(first).DamageHealth(2)
+			for i := 0; i < 2; i++ {
+				UpdateExplosions(e)
+			}
+
+			for _, b := range []*donburi.Entry{first, second} {
+				if !components.Explosive.Get(b).Exploded {
+					t.Fatalf("barrel at %v did not go off", center(b))
+				}
+			}
+		}},
+		{"destructible wall is worn down", func(t *testing.T, e *ecs.ECS, space *donburi.Entry) {
+			source := factory.CreateBarrel(e, 96, 96)
+			dresolv.Add(space, source)
+			wall := factory.CreateDestructibleWall(e, resolv.NewObject(160, 96, 32, 32))
+			dresolv.Add(space, wall)
+
+			explode(e, source, center(source), &components.ExplosiveData{Radius: 96, Damage: 4, Knockback: 8})
+
+			//48 from the blast, 0.5 of the damage rounded up
+			if got := components.Health.Get(wall).Ammount; got != 1 {
+				t.Errorf("wall health %d, want 1", got)
+			}
+			if !components.Block.Get(wall).Damaged {
+				t.Errorf("wall not marked damaged")
+			}
+		}},
+		{"grenade goes off when its time is up", func(t *testing.T, e *ecs.ECS, space *donburi.Entry) {
+			grenade := testBullet(e, space, 100, 100, dmath.NewVec2(1, 0), 0, components.BulletData{Lifetime: 1, Faction: components.FactionPlayer})
+			grenade.AddComponent(components.Explosive)
+			components.Explosive.SetValue(grenade, components.ExplosiveData{Radius: 64, Damage: 3, Knockback: 4})
+			actor := testActor(e, space, 120, 100, dmath.Vec2{}, 0)
+
+			UpdateBullets(e)
+			UpdateExplosions(e)
+			if components.Explosive.Get(grenade).Exploded {
+				t.Fatalf("grenade went off before its time")
+			}
+
+			GetClock(e).Tick += config.TickRate
+			UpdateBullets(e)
+			UpdateExplosions(e)
+			if !components.Explosive.Get(grenade).Exploded {
+				t.Fatalf("grenade did not go off")
+			}
+			if got := components.Health.Get(actor).Ammount; got >= 10 {
+				t.Errorf("actor next to the grenade not hurt")
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, space := blastWorld(t)
+			tt.run(t, e, space)
+		})
+	}
+}
@@ -1,10 +1,8 @@
 package systems
 
 import (
-	"fmt"
-	"time"
-
 	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/events"
 	"github.com/yohamta/donburi"
 	"github.com/yohamta/donburi/ecs"
 	"github.com/yohamta/donburi/filter"
@@ -12,18 +10,18 @@ import (
 
 func UpdateHealth(ecs *ecs.ECS) {
 	query := donburi.NewQuery(filter.Contains(components.Health))
+	clock := GetClock(ecs)
 
 	query.Each(ecs.World, func(e *donburi.Entry) {
 		health := components.Health.Get(e)
 
 		if health.Dead && !health.DeathLock {
-			//trigger death event
-			fmt.Println("just died!")
 			health.DeathLock = true
+			events.DeathEvent.Publish(ecs.World, events.Death{Entry: e})
 		}
 
 		if health.Hit {
-			if time.Now().Sub(health.HitTime).Seconds() >= health.Cooldown {
+			if clock.Since(health.HitTime) >= health.Cooldown {
 				health.Hit = false
 			}
 		}
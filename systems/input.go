@@ -0,0 +1,17 @@
+package systems
+
+import (
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/yohamta/donburi/ecs"
+)
+
+func UpdateInput(ecs *ecs.ECS) {
+	in := GetInput(ecs)
+
+	in.Previous = in.Current
+	in.Current = in.Source.Poll(GetClock(ecs).Tick)
+}
+
+func GetInput(ecs *ecs.ECS) *components.InputData {
+	return components.Input.Get(components.Input.MustFirst(ecs.World))
+}
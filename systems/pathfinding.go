@@ -0,0 +1,20 @@
+package systems
+
+import (
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/yohamta/donburi/ecs"
+)
+
+// UpdatePathFinding keeps the flow field pointed at the player and answers
+// the path requests the budget allows for this tick.
+func UpdatePathFinding(ecs *ecs.ECS) {
+	pf := components.PathFinder.Get(components.PathFinder.MustFirst(ecs.World))
+
+	player := components.Player.MustFirst(ecs.World)
+	if !components.Health.Get(player).Dead {
+		obj := components.Object.Get(player)
+		pf.UpdateFlow(obj.Position.X, obj.Position.Y)
+	}
+
+	pf.ProcessRequests()
+}
@@ -0,0 +1,64 @@
+package systems
+
+import (
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/events"
+	"github.com/AndriiPets/FishGame/factory"
+	dresolv "github.com/AndriiPets/FishGame/resolv"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	"github.com/yohamta/donburi/features/math"
+)
+
+// seconds before a dropped weapon can be collected again
+const dropPickupDelay = 1.0
+
+// OnPickupTrigger moves weapons the player stands on into their inventory.
+func OnPickupTrigger(ecs *ecs.ECS) func(w donburi.World, event events.Trigger) {
+	return func(w donburi.World, event events.Trigger) {
+		if _, ok := trigger_of(event, components.TriggerPickup); !ok {
+			return
+		}
+
+		e, p := event.Entry, event.Trigger
+		if !e.HasComponent(components.Inventory) || !e.HasComponent(components.Shooter) {
+			return
+		}
+		if e.HasComponent(components.Health) && components.Health.Get(e).Dead {
+			return
+		}
+
+		inv := components.Inventory.Get(e)
+		shooter := components.Shooter.Get(e)
+		pickup := components.Pickup.Get(p)
+		despawn := components.Despawnable.Get(p)
+		if despawn.DespawnRequest || GetClock(ecs).Since(pickup.SpawnTick) < pickup.Delay {
+			return
+		}
+
+		//ammo for the weapon in hand goes straight to the shooter
+		inv.Save(shooter)
+		slot, added := inv.Add(pickup.Weapon)
+		if !added {
+			return
+		}
+		if slot == inv.Current {
+			shooter.Reserve = inv.Slots[slot].Reserve
+		}
+
+		despawn.DespawnRequest = true
+	}
+}
+
+// dropWeapon leaves a weapon pickup in front of the entity.
+func dropWeapon(ecs *ecs.ECS, e *donburi.Entry, weapon components.WeaponSlot) {
+	space := components.Space.MustFirst(ecs.World)
+	obj := dresolv.GetObject(e)
+	attackVec := components.AttackVector.Get(e).Vec
+
+	pos := math.NewVec2(obj.Position.X, obj.Position.Y).Add(attackVec.MulScalar(8))
+	pickup := factory.CreateWeaponPickup(ecs, pos.X, pos.Y, weapon, GetClock(ecs).Tick, dropPickupDelay)
+
+	dresolv.Add(space, pickup)
+}
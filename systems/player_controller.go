@@ -4,13 +4,11 @@ import (
 	"fmt"
 	"image/color"
 	mmath "math"
-	"time"
 
 	"github.com/AndriiPets/FishGame/components"
 	"github.com/AndriiPets/FishGame/factory"
 	"github.com/AndriiPets/FishGame/tags"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/yohamta/donburi"
 	"github.com/yohamta/donburi/ecs"
@@ -19,8 +17,6 @@ import (
 	dresolv "github.com/AndriiPets/FishGame/resolv"
 )
 
-var dashVec math.Vec2
-
 func UpdatePlayer(ecs *ecs.ECS) {
 	playerEntity, _ := components.Player.First(ecs.World)
 	playerVelocity := components.Velocity.Get(playerEntity)
@@ -30,6 +26,16 @@ func UpdatePlayer(ecs *ecs.ECS) {
 	playerObj := dresolv.GetObject(playerEntity)
 
 	shooter := components.Shooter.Get(playerEntity)
+	in := GetInput(ecs)
+	clock := GetClock(ecs)
+
+	//the dead don't move or shoot
+	if components.Health.Get(playerEntity).Dead {
+		playerVelocity.Speed = 0
+		playerVelocity.Vel = math.NewVec2(0, 0)
+		updatePlayerState(playerEntity, components.PlayerStateIdle)
+		return
+	}
 
 	//MOVEMENT
 	//dx, dy := 0.0, 0.0 //direction vector
@@ -43,42 +49,10 @@ func UpdatePlayer(ecs *ecs.ECS) {
 	if !player.IsDashing {
 
 		//update direction
-		if ebiten.IsKeyPressed(ebiten.KeyW) {
-			playerVelocity.Vel = math.NewVec2(0.0, -1.0)
-			playerVelocity.Speed += accel
-		}
-
-		if ebiten.IsKeyPressed(ebiten.KeyS) {
-			playerVelocity.Vel = math.NewVec2(0.0, 1.0)
-			playerVelocity.Speed += accel
-		}
-
-		if ebiten.IsKeyPressed(ebiten.KeyA) {
-			playerVelocity.Vel = math.NewVec2(-1.0, 0.0)
-			playerVelocity.Speed += accel
-		}
-
-		if ebiten.IsKeyPressed(ebiten.KeyD) {
-			playerVelocity.Vel = math.NewVec2(1.0, 0.0)
-			playerVelocity.Speed += accel
-		}
-
-		//diagonal movement
-		if ebiten.IsKeyPressed(ebiten.KeyW) && ebiten.IsKeyPressed(ebiten.KeyA) {
-			playerVelocity.Vel = math.NewVec2(-1.0, -1.0)
-			playerVelocity.Speed += accel
-		}
-		if ebiten.IsKeyPressed(ebiten.KeyW) && ebiten.IsKeyPressed(ebiten.KeyD) {
-			playerVelocity.Vel = math.NewVec2(1.0, -1.0)
-			playerVelocity.Speed += accel
-		}
-		if ebiten.IsKeyPressed(ebiten.KeyS) && ebiten.IsKeyPressed(ebiten.KeyA) {
-			playerVelocity.Vel = math.NewVec2(-1.0, 1.0)
-			playerVelocity.Speed += accel
-		}
-		if ebiten.IsKeyPressed(ebiten.KeyS) && ebiten.IsKeyPressed(ebiten.KeyD) {
-			playerVelocity.Vel = math.NewVec2(1.0, 1.0)
-			playerVelocity.Speed += accel
+		//analog sticks accelerate proportionally to how far they are pushed
+		if move := in.Current.Move; !move.IsZero() {
+			playerVelocity.Vel = move
+			playerVelocity.Speed += accel * mmath.Min(move.Magnitude(), 1)
 		}
 		//
 
@@ -99,29 +73,29 @@ func UpdatePlayer(ecs *ecs.ECS) {
 		//fmt.Println(playerVelocity.Speed)
 
 		//dash controls
-		if ebiten.IsKeyPressed(ebiten.KeyShiftLeft) {
+		if in.Current.Dash {
 			player.IsDashing = true
 			fmt.Println(playerVelocity.Speed)
 
-			player.DashTimer = time.Now()
+			player.DashTimer = clock.Tick
 
 			if playerVelocity.Vel.IsZero() {
-				dashVec = attackVec
+				player.DashVec = attackVec
 			} else {
-				dashVec = playerVelocity.Vel
+				player.DashVec = playerVelocity.Vel
 			}
 
 		}
 
 	} else {
 
-		playerVelocity.Vel = dashVec
+		playerVelocity.Vel = player.DashVec
 
 		playerVelocity.Speed = maxSpeed * 2
 
 	}
 
-	if time.Now().Sub(player.DashTimer).Seconds() >= dashCooldown {
+	if clock.Since(player.DashTimer) >= dashCooldown {
 		player.IsDashing = false
 	}
 
@@ -157,17 +131,26 @@ func UpdatePlayer(ecs *ecs.ECS) {
 	//updatePlayerDir(playerEntity, flip)
 
 	//Shooting
-	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-		if shooter.CanFire && !player.IsDashing {
-			shooter.Fire = true
-		}
+	//the trigger is held every tick, the shooter decides when a shot comes out
+	if in.Current.Fire && !player.IsDashing {
+		shooter.Fire = true
+	}
+
+	if in.ReloadPressed() {
+		shooter.Reload = true
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyR) {
-		if shooter.Type == "default" {
-			shooter.Type = "bouncer"
-		} else {
-			shooter.Type = "default"
+	//weapon inventory
+	inv := components.Inventory.Get(playerEntity)
+	if dir := in.CyclePressed(); dir != 0 {
+		inv.Cycle(dir, shooter)
+	}
+	if slot := in.SlotPressed(); slot > 0 && slot-1 != inv.Current {
+		inv.Equip(slot-1, shooter)
+	}
+	if in.DropPressed() {
+		if weapon, ok := inv.Remove(shooter); ok {
+			dropWeapon(ecs, playerEntity, weapon)
 		}
 	}
 
@@ -193,14 +176,15 @@ func playerSpawnDustParticle(ecs *ecs.ECS, playerEntry *donburi.Entry, cooldown
 	player := components.Player.Get(playerEntry)
 	anim := components.Animation.Get(playerEntry)
 	playerObj := dresolv.GetObject(playerEntry)
+	clock := GetClock(ecs)
 
 	//dust particle spawn
 	if !player.ParticleSpawn {
-		player.ParticleTimer = time.Now()
+		player.ParticleTimer = clock.Tick
 		player.ParticleSpawn = true
 	}
 
-	if time.Now().Sub(player.ParticleTimer).Seconds() >= cooldown {
+	if clock.Since(player.ParticleTimer) >= cooldown {
 		factory.CreateParticle(
 			ecs,
 			playerObj.Position.X+(playerObj.Size.X/2),
@@ -0,0 +1,159 @@
+package systems
+
+import (
+	"math"
+
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/events"
+	"github.com/AndriiPets/FishGame/factory"
+	dresolv "github.com/AndriiPets/FishGame/resolv"
+	"github.com/AndriiPets/FishGame/tags"
+	"github.com/AndriiPets/FishGame/utils"
+
+	"github.com/quasilyte/pathing"
+	"github.com/solarlune/resolv"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
+
+// OnRoomTrigger locks a room once the living player is all the way inside
+// with enemies, a room without any counts as cleared.
+func OnRoomTrigger(ecs *ecs.ECS) func(w donburi.World, event events.Trigger) {
+	return func(w donburi.World, event events.Trigger) {
+		if _, ok := trigger_of(event, components.TriggerRoom); !ok {
+			return
+		}
+
+		player := event.Entry
+		room := components.Room.Get(event.Trigger)
+		if room.Locked || room.Cleared || !player.HasComponent(components.Player) || components.Health.Get(player).Dead {
+			return
+		}
+
+		area := dresolv.GetObject(event.Trigger)
+		if !contains(area, dresolv.GetObject(player)) {
+			return
+		}
+
+		room.Enemies = room.Enemies[:0]
+		tags.Enemy.Each(w, func(e *donburi.Entry) {
+			if !components.Health.Get(e).Dead && overlaps(area, dresolv.GetObject(e)) {
+				room.Enemies = append(room.Enemies, e.Entity())
+			}
+		})
+
+		if len(room.Enemies) == 0 {
+			room.Cleared = true
+			return
+		}
+
+		lock_room(ecs, room, area)
+	}
+}
+
+// UpdateRooms opens locked rooms once every enemy inside is dead.
+func UpdateRooms(ecs *ecs.ECS) {
+	components.Room.Each(ecs.World, func(e *donburi.Entry) {
+		room := components.Room.Get(e)
+		if !room.Locked {
+			return
+		}
+
+		for _, enemy := range room.Enemies {
+			if ecs.World.Valid(enemy) && !components.Health.Get(ecs.World.Entry(enemy)).Dead {
+				return
+			}
+		}
+
+		unlock_room(ecs, room)
+	})
+}
+
+// lock_room puts a wall in every door of the room and closes them for pathing.
+// The doors are worked out again first so holes shot into its walls get sealed too.
+func lock_room(ecs *ecs.ECS, room *components.RoomData, area *resolv.Object) {
+	space := components.Space.MustFirst(ecs.World)
+	pf := components.PathFinder.Get(components.PathFinder.MustFirst(ecs.World))
+
+	room.Doors = room_doors(pf, area)
+	for _, c := range room.Doors {
+		clear_door(ecs, components.Space.Get(space), c, area)
+
+		door := factory.CreateDoor(ecs, float64(c.X*config.BlockSize), float64(c.Y*config.BlockSize))
+		dresolv.Add(space, door)
+
+		room.Blocks = append(room.Blocks, door.Entity())
+		pf.SetWalkable(c, false)
+	}
+
+	room.Locked = true
+}
+
+// unlock_room takes the doors down again.
+func unlock_room(ecs *ecs.ECS, room *components.RoomData) {
+	pf := components.PathFinder.Get(components.PathFinder.MustFirst(ecs.World))
+
+	for _, door := range room.Blocks {
+		if ecs.World.Valid(door) {
+			components.Despawnable.Get(ecs.World.Entry(door)).DespawnRequest = true
+		}
+	}
+	for _, c := range room.Doors {
+		pf.SetWalkable(c, true)
+	}
+
+	room.Blocks = nil
+	room.Locked = false
+	room.Cleared = true
+}
+
+// clear_door moves the actors standing in a door cell into the room so the
+// door does not close on them.
+func clear_door(ecs *ecs.ECS, space *resolv.Space, c pathing.GridCoord, area *resolv.Object) {
+	size := float64(config.BlockSize)
+	cell := resolv.NewObject(float64(c.X)*size, float64(c.Y)*size, size, size)
+
+	for _, o := range space.CheckCells(c.X, c.Y, 1, 1) {
+		actor, ok := dresolv.Entry(ecs.World, o)
+		if !ok || !actor.HasComponent(components.Collider) || !overlaps(cell, o) {
+			continue
+		}
+		if components.Collider.Get(actor).Layer&(components.LayerPlayer|components.LayerEnemy) == 0 {
+			continue
+		}
+
+		o.Position.X = math.Max(area.Position.X, math.Min(o.Position.X, area.Position.X+area.Size.X-o.Size.X))
+		o.Position.Y = math.Max(area.Position.Y, math.Min(o.Position.Y, area.Position.Y+area.Size.Y-o.Size.Y))
+		o.Update()
+	}
+}
+
+// room_doors returns the walkable cells on the ring around a room area.
+func room_doors(pf *utils.PathFinder, area *resolv.Object) []pathing.GridCoord {
+	x0, y0 := int(area.Position.X)/config.BlockSize, int(area.Position.Y)/config.BlockSize
+	x1, y1 := int(area.Position.X+area.Size.X)/config.BlockSize, int(area.Position.Y+area.Size.Y)/config.BlockSize
+
+	var doors []pathing.GridCoord
+	for y := y0 - 1; y <= y1; y++ {
+		for x := x0 - 1; x <= x1; x++ {
+			ring := x == x0-1 || x == x1 || y == y0-1 || y == y1
+			if !ring || x < 0 || y < 0 || x >= pf.Grid.NumCols() || y >= pf.Grid.NumRows() {
+				continue
+			}
+
+			c := pathing.GridCoord{X: x, Y: y}
+			if pf.Grid.GetCellTile(c) == utils.TileFloor {
+				doors = append(doors, c)
+			}
+		}
+	}
+
+	return doors
+}
+
+// contains is true when b lies completely inside a.
+func contains(a, b *resolv.Object) bool {
+	return b.Position.X >= a.Position.X && b.Position.X+b.Size.X <= a.Position.X+a.Size.X &&
+		b.Position.Y >= a.Position.Y && b.Position.Y+b.Size.Y <= a.Position.Y+a.Size.Y
+}
@@ -0,0 +1,102 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/AndriiPets/FishGame/archetypes"
+	"github.com/AndriiPets/FishGame/assets"
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/factory"
+	dresolv "github.com/AndriiPets/FishGame/resolv"
+	"github.com/AndriiPets/FishGame/utils"
+	"github.com/quasilyte/pathing"
+	"github.com/solarlune/resolv"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	dmath "github.com/yohamta/donburi/features/math"
+)
+
+// roomLayout is a 2x2 room with one doorway on its left side.
+var roomLayout = []string{
+	"xxxxxx",
+	"xxxxxx",
+	"x  xxx",
+	"xx  x ",
+	"xxxxxx",
+}
+
+// testPathFinder adds a path finder over the layout, x is a wall.
+func testPathFinder(e *ecs.ECS, layout []string) *utils.PathFinder {
+	data := make([][]rune, len(layout))
+	for y, row := range layout {
+		data[y] = []rune(row)
+	}
+	pf := utils.NewPathFinder()
+	pf.GenerateLayout(data, 'x')
+	components.PathFinder.Set(archetypes.PathFinder.Spawn(e), pf)
+	return pf
+}
+
+func TestLockRoom(t *testing.T) {
+	if err := assets.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	doorway := pathing.GridCoord{X: 1, Y: 2}
+	hole := pathing.GridCoord{X: 4, Y: 3}
+
+	tests := []struct {
+		name   string
+		shot   []pathing.GridCoord //walls destroyed before the lock
+		doors  []pathing.GridCoord
+		inDoor bool //an actor stands in the doorway as it locks
+	}{
+		{"layout doorway", nil, []pathing.GridCoord{doorway}, false},
+		{"wall shot open", []pathing.GridCoord{hole}, []pathing.GridCoord{doorway, hole}, false},
+		{"actor in the doorway", nil, []pathing.GridCoord{doorway}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := ecs.NewECS(donburi.NewWorld())
+			factory.CreateClock(e, 1/float64(config.TickRate))
+			space := factory.CreateSpace(e)
+
+			pf := testPathFinder(e, roomLayout)
+
+			for _, c := range tt.shot {
+				pf.SetWalkable(c, true)
+			}
+
+			size := float64(config.BlockSize)
+			area := resolv.NewObject(2*size, 2*size, 2*size, 2*size)
+			room := &components.RoomData{}
+
+			var actor *donburi.Entry
+			if tt.inDoor {
+				actor = testActor(e, space, float64(doorway.X)*size+8, float64(doorway.Y)*size+8, dmath.Vec2{}, 0)
+			}
+
+			lock_room(e, room, area)
+			if actor != nil && !contains(area, dresolv.GetObject(actor)) {
+				t.Errorf("actor left in the doorway at %v", dresolv.GetObject(actor).Position)
+			}
+			if len(room.Blocks) != len(tt.doors) {
+				t.Fatalf("%d doors put up, want %d", len(room.Blocks), len(tt.doors))
+			}
+			for _, c := range tt.doors {
+				if pf.Grid.GetCellTile(c) != utils.TileWall {
+					t.Errorf("%v left open", c)
+				}
+			}
+
+			unlock_room(e, room)
+			for _, c := range tt.doors {
+				if pf.Grid.GetCellTile(c) != utils.TileFloor {
+					t.Errorf("%v still closed", c)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package systems
 
 import (
 	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/config"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/yohamta/donburi/ecs"
@@ -11,8 +12,9 @@ func UpdateSettings(ecs *ecs.ECS) {
 	settings := GetOrCreateSettings(ecs)
 
 	if inpututil.IsKeyJustPressed(ebiten.KeyF1) {
-		settings.Debug = !settings.Debug
+		config.C.Debug = !config.C.Debug
 	}
+	settings.Debug = config.C.Debug
 
 	if inpututil.IsKeyJustPressed(ebiten.KeyF2) {
 		settings.ShowHelpText = !settings.ShowHelpText
@@ -23,6 +25,7 @@ func GetOrCreateSettings(ecs *ecs.ECS) *components.SettingsData {
 	if _, ok := components.Settings.First(ecs.World); !ok {
 		ent := ecs.World.Entry(ecs.World.Create(components.Settings))
 		components.Settings.SetValue(ent, components.SettingsData{
+			Debug:        config.C.Debug,
 			ShowHelpText: true,
 		})
 	}
@@ -3,7 +3,6 @@ package systems
 import (
 	"image/color"
 	"math"
-	"time"
 
 	"github.com/AndriiPets/FishGame/archetypes"
 	"github.com/AndriiPets/FishGame/components"
@@ -25,43 +24,161 @@ import (
 )
 
 func UpdateShooters(ecs *ecs.ECS) {
-	query := donburi.NewQuery(filter.Contains(components.Shooter, components.AttackVector))
+	//weapon sprites share the holder's shooter data, only the holder fires
+	query := donburi.NewQuery(filter.And(
+		filter.Contains(components.Shooter, components.AttackVector),
+		filter.Not(filter.Contains(tags.WeaponSprite)),
+	))
+	clock := GetClock(ecs)
 
 	query.Each(ecs.World, func(e *donburi.Entry) {
 		shooter := components.Shooter.Get(e)
 		weaponData := resources.WeaponMap[shooter.Type]
 
-		if shooter.Fire && shooter.CanFire {
+		if e.HasComponent(components.Health) && components.Health.Get(e).Dead {
+			shooter.Fire = false
+			return
+		}
+
+		updateReload(ecs, e, shooter, weaponData)
+
+		pulled := shooter.Fire && !shooter.TriggerHeld
+		shoot := false
+
+		switch {
+		case weaponData.Kind == resources.WeaponCharge:
+			//charge weapons build up while fire is held and shoot on release
+			if shooter.Fire && shooter.CanFire && !shooter.Charging && !shooter.Empty() && !shooter.Reloading {
+				shooter.Charging = true
+				shooter.ChargeStart = clock.Tick
+			}
+			if shooter.Charging && !shooter.Fire {
+				shooter.Charging = false
+				shoot = true
+			}
+		case shooter.BurstLeft > 0:
+			//a started burst finishes without holding the trigger
+			shoot = true
+		case weaponData.FireMode == resources.FireAuto:
+			shoot = shooter.Fire
+		default:
+			shoot = pulled
+		}
+
+		//dry magazines click once per trigger pull and start a reload
+		if shooter.Empty() && !shooter.Reloading {
+			if pulled && shooter.CanFire {
+				events.EmptyClickEvent.Publish(ecs.World, events.EmptyClick{Entry: e})
+				startReload(ecs, e, shooter)
+			}
+			shooter.BurstLeft = 0
+			shoot = false
+		}
+
+		if shoot && shooter.CanFire && !shooter.Reloading {
 			//fmt.Println("Fire shooter\nCooldown:", weaponData.Cooldown)
-			//spawn bullet
-			spawnBullet(e, ecs)
+			switch weaponData.Kind {
+			case resources.WeaponHitscan:
+				fireHitscan(e, ecs)
+			case resources.WeaponMelee:
+				fireMelee(e, ecs)
+			case resources.WeaponCharge:
+				spawnBullet(e, ecs, chargeAmount(ecs, shooter))
+			default:
+				spawnBullet(e, ecs, 0)
+			}
+
+			if weaponData.Magazine > 0 {
+				shooter.Ammo--
+			}
 
 			//recoil screen shake if fired by player
 			if e.HasComponent(components.Player) {
 				events.ScreenShakeEvent.Publish(ecs.World, events.ScreenShake{Type: "recoil"})
 			}
 
+			//loud weapons give the shooter away
+			if weaponData.Noise > 0 {
+				obj := components.Object.Get(e)
+				events.NoiseEvent.Publish(ecs.World, events.Noise{
+					Source:   e,
+					Position: dmath.NewVec2(obj.Position.X, obj.Position.Y),
+					Radius:   weaponData.Noise,
+				})
+			}
+
 			//weapon sprite recoil
 			events.WeaponRecoilEvent.Publish(ecs.World, events.WeaponRecoil{Entry: e})
 			shooter.WeaponFlash = true
 
-			shooter.FireTime = time.Now()
+			//bursts start on a trigger pull and space their shots by BurstDelay
+			shooter.Cooldown = weaponData.Cooldown
+			if weaponData.FireMode == resources.FireBurst && weaponData.Kind != resources.WeaponCharge {
+				if shooter.BurstLeft == 0 {
+					shooter.BurstLeft = weaponData.BurstCount
+				}
+				shooter.BurstLeft--
+				if shooter.BurstLeft > 0 {
+					shooter.Cooldown = weaponData.BurstDelay
+				}
+			}
+
+			shooter.FireTime = clock.Tick
 			shooter.CanFire = false
-			shooter.Fire = false
 		}
 
 		if !shooter.CanFire {
-			if time.Now().Sub(shooter.FireTime).Seconds() >= weaponData.Cooldown {
+			if clock.Since(shooter.FireTime) >= shooter.Cooldown {
 				shooter.CanFire = true
 				//fmt.Println("Cooldown over, can fire")
 			}
 		}
 
+		//fire is requested again every tick the trigger is held
+		shooter.TriggerHeld = shooter.Fire
+		shooter.Fire = false
 	})
 }
 
+// updateReload starts requested reloads and refills the magazine once the
+// reload time has passed.
+func updateReload(ecs *ecs.ECS, e *donburi.Entry, shooter *components.ShooterData, weaponData resources.Weapon) {
+	if shooter.Reload {
+		startReload(ecs, e, shooter)
+		shooter.Reload = false
+	}
+
+	if !shooter.Reloading || GetClock(ecs).Since(shooter.ReloadStart) < weaponData.ReloadTime {
+		return
+	}
+
+	need := weaponData.Magazine - shooter.Ammo
+	if !shooter.InfiniteAmmo {
+		need = int(math.Min(float64(need), float64(shooter.Reserve)))
+		shooter.Reserve -= need
+	}
+	shooter.Ammo += need
+	shooter.Reloading = false
+
+	events.ReloadFinishEvent.Publish(ecs.World, events.Reload{Entry: e})
+}
+
+func startReload(ecs *ecs.ECS, e *donburi.Entry, shooter *components.ShooterData) {
+	if !shooter.CanReload() {
+		return
+	}
+
+	shooter.Reloading = true
+	shooter.ReloadStart = GetClock(ecs).Tick
+	shooter.Charging = false
+	shooter.BurstLeft = 0
+
+	events.ReloadStartEvent.Publish(ecs.World, events.Reload{Entry: e})
+}
+
 func DrawWeaponFlash(ecs *ecs.ECS, screen *ebiten.Image) {
 	query := donburi.NewQuery(filter.Contains(components.Shooter, components.AttackVector))
+	clock := GetClock(ecs)
 
 	query.Each(ecs.World, func(e *donburi.Entry) {
 		shooter := components.Shooter.Get(e)
@@ -72,7 +189,7 @@ func DrawWeaponFlash(ecs *ecs.ECS, screen *ebiten.Image) {
 
 		if !shooter.CanFire {
 
-			if time.Now().Sub(shooter.FireTime).Seconds() <= cooldown {
+			if clock.Since(shooter.FireTime) <= cooldown {
 				vector.DrawFilledCircle(screen, float32(spawnPosition.X), float32(spawnPosition.Y), 7, color.RGBA{225, 225, 225, 255}, false)
 				//vector.DrawFilledRect(screen, float32(spawnPosition.X), float32(spawnPosition.Y), 32, 32, color.RGBA{225, 225, 225, 255}, false)
 
@@ -82,46 +199,84 @@ func DrawWeaponFlash(ecs *ecs.ECS, screen *ebiten.Image) {
 	})
 }
 
-var bulletSpawnMap = map[string]*archetypes.Archetype{
-	"normal": archetypes.Bullet,
-	"bounce": archetypes.BouncerBullet,
-}
-
-func spawnBullet(e *donburi.Entry, ecs *ecs.ECS) {
+// spawnBullet fires the weapon's projectiles. charge from 0 to 1 scales speed
+// and damage up to the weapon's ChargeMax.
+func spawnBullet(e *donburi.Entry, ecs *ecs.ECS, charge float64) {
 
 	shooter := components.Shooter.Get(e)
 	attackVec := components.AttackVector.Get(e).Vec
 	space := components.Space.MustFirst(ecs.World)
+	rng := GetRandom(ecs)
+	clock := GetClock(ecs)
 
 	weaponData := resources.WeaponMap[shooter.Type]
-	bulletData := resources.ProjectileMap[weaponData.Bullet]
-	//fmt.Println(weaponData.Bullet)
-
-	bullet := bulletSpawnMap[weaponData.Bullet].Spawn(ecs)
-
-	//setup animation sprite
-	animation := components.Animation.Get(bullet)
-	bulletComp := components.Bullet.Get(bullet)
+	bulletData := resources.ProjectileMap[weaponData.Projectile]
 
-	//rotate image
-	angle := math.Atan2(attackVec.Y, attackVec.X)
-	animation.Rotation = angle
-	animation.Animation = bulletComp.Animation()
+	spread := weaponData.Spread * math.Pi / 180
+	baseAngle := math.Atan2(attackVec.Y, attackVec.X)
 
-	//bullet spawn position
-	spawnPosition := shooter.HolderPosition.Add(attackVec.MulScalar(24))
+	scale := 1.0
+	if weaponData.ChargeMax > 1 {
+		scale += (weaponData.ChargeMax - 1) * charge
+	}
 
-	obj := resolv.NewObject(spawnPosition.X, spawnPosition.Y, bulletData.Size, bulletData.Size)
-	obj.AddTags("bullet")
-	obj.Data = bullet.Id()
-	dresolv.SetObject(bullet, obj)
+	for i := 0; i < weaponData.Pellets; i++ {
+		bullet := archetypes.Bullet.Spawn(ecs)
 
-	components.Velocity.SetValue(bullet, components.VelocityData{
-		Vel:   attackVec,
-		Speed: bulletData.Speed,
-	})
+		//every pellet gets its own deviation inside the spread cone
+		angle := baseAngle
+		if spread > 0 {
+			angle += (rng.Float64() - 0.5) * spread
+		}
+		dir := dmath.NewVec2(math.Cos(angle), math.Sin(angle))
+
+		components.Bullet.SetValue(bullet, components.BulletData{
+			Sprite:    bulletData.Sprite,
+			SpawnTick: clock.Tick,
+			Lifetime:  bulletData.Lifetime,
+			Bounces:   bulletData.Bounces,
+
+			Damage:       int(math.Round(float64(weaponData.Damage) * scale)),
+			Knockback:    weaponData.Knockback,
+			Owner:        e.Entity(),
+			Faction:      components.FactionOf(e),
+			FriendlyFire: weaponData.FriendlyFire,
+		})
+
+		//setup animation sprite
+		animation := components.Animation.Get(bullet)
+		bulletComp := components.Bullet.Get(bullet)
+
+		//rotate image
+		animation.Rotation = angle
+		animation.Animation = bulletComp.Animation()
+
+		//bullet spawn position
+		spawnPosition := shooter.HolderPosition.Add(attackVec.MulScalar(24))
+
+		obj := resolv.NewObject(spawnPosition.X, spawnPosition.Y, bulletData.Size, bulletData.Size)
+		dresolv.SetObject(bullet, obj)
+
+		//projectiles only run into actors they can hurt
+		collider := components.ProjectileCollider(components.FactionOf(e), weaponData.FriendlyFire)
+		dresolv.SetCollider(bullet, collider.Layer, collider.Mask)
+
+		components.Velocity.SetValue(bullet, components.VelocityData{
+			Vel:   dir,
+			Speed: bulletData.Speed * scale,
+		})
+
+		if blast := bulletData.Blast; blast != nil {
+			bullet.AddComponent(components.Explosive)
+			components.Explosive.SetValue(bullet, components.ExplosiveData{
+				Radius:    blast.Radius,
+				Damage:    blast.Damage,
+				Knockback: blast.Knockback,
+			})
+		}
 
-	dresolv.Add(space, bullet)
+		dresolv.Add(space, bullet)
+	}
 }
 
 func UpdateWeaponSprite(ecs *ecs.ECS) {
@@ -133,39 +288,67 @@ func UpdateWeaponSprite(ecs *ecs.ECS) {
 		attVec := components.AttackVector.Get(e)
 		weaponData := resources.WeaponMap[shooter.Type]
 
+		//switch sprite when the holder changes weapon
+		if shooter.SpriteType != shooter.Type {
+			anim.Animation = shooter.Animation()
+			shooter.SpriteType = shooter.Type
+		}
+
 		//update animation obj position based on shooter position
 		var pos dmath.Vec2
 		ran := shooter.HoldRange
+		swing := 0.0 //melee rotation offset in radians
+		melee := weaponData.Kind == resources.WeaponMelee
+		halfArc := weaponData.Arc * math.Pi / 360
 
 		//if on cooldown recoil sprite backwards
-		if shooter.WeaponFlash {
+		if shooter.WeaponFlash && melee {
+			//sweep the blade across the arc instead of recoiling
+			anim.Ease = gween.New(float32(-halfArc), float32(halfArc), float32(shooter.Cooldown), ease.OutQuad)
+			swing = -halfArc
+		} else if shooter.WeaponFlash {
 			//weapon fierd apply new easing function for recoil
-			anim.Ease = gween.New(1, float32(shooter.HoldRange), float32(weaponData.Cooldown), ease.Linear)
+			anim.Ease = gween.New(1, float32(shooter.HoldRange), float32(shooter.Cooldown), ease.Linear)
 			ran = 1
 
 			//Spawn weapon flash animation
-			spawnPosition := shooter.HolderPosition.Add(attVec.Vec.MulScalar(37))
-			factory.CreateParticle(
-				ecs,
-				spawnPosition.X,
-				spawnPosition.Y,
-				factory.ParticleGunFlash,
-				anim.Rotation,
-				anim.FlipH,
-				anim.FlipV,
-			)
+			if weaponData.MuzzleFlash != "" {
+				spawnPosition := shooter.HolderPosition.Add(attVec.Vec.MulScalar(37))
+				factory.CreateEffect(
+					ecs,
+					spawnPosition.X,
+					spawnPosition.Y,
+					weaponData.MuzzleFlash,
+					anim.Rotation,
+					anim.FlipH,
+					anim.FlipV,
+				)
+			}
 			shooter.WeaponFlash = false
 
 		}
 		if !shooter.CanFire {
 			//update weapon position based on easing function
-			curr, finish := anim.Ease.Update(float32(ecs.Time.DeltaTime().Seconds()))
-			if !finish {
+			curr, finish := anim.Ease.Update(float32(DeltaTime(ecs)))
+			if !finish && melee {
+				swing = float64(curr)
+			} else if !finish {
 				ran = float64(curr)
 			}
 		}
 
-		pos = shooter.HolderPosition.Add(attVec.Vec.MulScalar(ran))
+		//draw the bow back while it charges
+		if shooter.Charging {
+			ran = shooter.HoldRange * (1 - 0.5*chargeAmount(ecs, shooter))
+		}
+
+		aim := attVec.Vec
+		if swing != 0 {
+			sin, cos := math.Sincos(swing)
+			aim = dmath.NewVec2(aim.X*cos-aim.Y*sin, aim.X*sin+aim.Y*cos)
+		}
+
+		pos = shooter.HolderPosition.Add(aim.MulScalar(ran))
 
 		obj := dresolv.GetObject(e)
 
@@ -173,7 +356,7 @@ func UpdateWeaponSprite(ecs *ecs.ECS) {
 		obj.Position.Y = pos.Y
 
 		//calculate weapon sprite rotation
-		angle := math.Atan2(attVec.Vec.Y, attVec.Vec.X)
+		angle := math.Atan2(aim.Y, aim.X)
 		anim.Rotation = angle
 
 		//flip weapon sprite
@@ -0,0 +1,144 @@
+package systems
+
+import (
+	"image/color"
+
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/events"
+	dresolv "github.com/AndriiPets/FishGame/resolv"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/solarlune/resolv"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	dmath "github.com/yohamta/donburi/features/math"
+	"github.com/yohamta/donburi/filter"
+)
+
+// UpdateTriggers publishes an enter event for every entry that started to
+// overlap a trigger, a stay event every tick it overlaps and an exit event once
+// it left. Entries that were removed from the world don't exit.
+func UpdateTriggers(ecs *ecs.ECS) {
+	query := donburi.NewQuery(filter.Contains(components.Trigger, components.Object))
+	space := components.Space.Get(components.Space.MustFirst(ecs.World))
+
+	query.Each(ecs.World, func(t *donburi.Entry) {
+		trigger := components.Trigger.Get(t)
+		obj := dresolv.GetObject(t)
+
+		sx, sy := space.WorldToSpace(obj.Position.X, obj.Position.Y)
+		ex, ey := space.WorldToSpace(obj.Position.X+obj.Size.X, obj.Position.Y+obj.Size.Y)
+
+		var inside []donburi.Entity
+		for _, o := range space.CheckCells(sx, sy, ex-sx+1, ey-sy+1) {
+			if o == obj || !overlaps(obj, o) {
+				continue
+			}
+
+			e, ok := dresolv.Entry(ecs.World, o)
+			if !ok || !e.HasComponent(components.Collider) || components.Collider.Get(e).Layer&trigger.Mask == 0 || has_entity(inside, e.Entity()) {
+				continue
+			}
+			inside = append(inside, e.Entity())
+
+			event := events.Trigger{Trigger: t, Entry: e}
+			if !has_entity(trigger.Inside, e.Entity()) {
+				events.TriggerEnterEvent.Publish(ecs.World, event)
+			}
+			events.TriggerStayEvent.Publish(ecs.World, event)
+		}
+
+		for _, entity := range trigger.Inside {
+			if !has_entity(inside, entity) && ecs.World.Valid(entity) {
+				events.TriggerExitEvent.Publish(ecs.World, events.Trigger{Trigger: t, Entry: ecs.World.Entry(entity)})
+			}
+		}
+
+		trigger.Inside = inside
+	})
+}
+
+// trigger_of returns the trigger of the event if it is still there and of the kind.
+func trigger_of(event events.Trigger, kind components.TriggerKind) (*components.TriggerData, bool) {
+	if !event.Trigger.Valid() || !event.Entry.Valid() {
+		return nil, false
+	}
+
+	trigger := components.Trigger.Get(event.Trigger)
+	return trigger, trigger.Kind == kind
+}
+
+// OnTrapTrigger hurts living actors stepping on a trap.
+func OnTrapTrigger(ecs *ecs.ECS) func(w donburi.World, event events.Trigger) {
+	return func(w donburi.World, event events.Trigger) {
+		trap, ok := trigger_of(event, components.TriggerTrap)
+		if !ok || !event.Entry.HasComponent(components.Health) {
+			return
+		}
+
+		health := components.Health.Get(event.Entry)
+		if health.Dead || trap.Damage <= 0 {
+			return
+		}
+
+		//actors without a velocity are hit in place
+		var dir dmath.Vec2
+		if event.Entry.HasComponent(components.Velocity) {
+			dir = components.Velocity.Get(event.Entry).Vel
+		}
+
+		apply_hit(ecs, event.Entry, dir, 0)
+		health.DamageHealth(trap.Damage)
+	}
+}
+
+// overlaps is true when the objects share some area, touching edges don't count.
+func overlaps(a, b *resolv.Object) bool {
+	return a.Position.X < b.Position.X+b.Size.X && b.Position.X < a.Position.X+a.Size.X &&
+		a.Position.Y < b.Position.Y+b.Size.Y && b.Position.Y < a.Position.Y+a.Size.Y
+}
+
+func has_entity(entities []donburi.Entity, e donburi.Entity) bool {
+	for _, other := range entities {
+		if other == e {
+			return true
+		}
+	}
+	return false
+}
+
+// DrawTraps draws the spikes of every trap.
+func DrawTraps(ecs *ecs.ECS, screen *ebiten.Image) {
+	components.Trigger.Each(ecs.World, func(e *donburi.Entry) {
+		if components.Trigger.Get(e).Kind != components.TriggerTrap {
+			return
+		}
+
+		o := dresolv.GetObject(e)
+		x, y, w, h := float32(o.Position.X), float32(o.Position.Y), float32(o.Size.X), float32(o.Size.Y)
+
+		//a plate with a grid of spikes sticking out
+		vector.DrawFilledRect(screen, x+2, y+2, w-4, h-4, color.RGBA{90, 84, 78, 255}, false)
+		for sy := y + h/4; sy < y+h; sy += h / 4 {
+			for sx := x + w/4; sx < x+w; sx += w / 4 {
+				vector.StrokeLine(screen, sx-3, sy+3, sx, sy-3, 1, color.RGBA{200, 200, 200, 255}, false)
+				vector.StrokeLine(screen, sx, sy-3, sx+3, sy+3, 1, color.RGBA{200, 200, 200, 255}, false)
+			}
+		}
+	})
+}
+
+// DrawPrompts shows the text of the prompts the player stands in.
+func DrawPrompts(ecs *ecs.ECS, screen *ebiten.Image) {
+	components.Trigger.Each(ecs.World, func(e *donburi.Entry) {
+		trigger := components.Trigger.Get(e)
+		if trigger.Kind != components.TriggerPrompt || len(trigger.Inside) == 0 {
+			return
+		}
+
+		o := dresolv.GetObject(e)
+		ebitenutil.DebugPrintAt(screen, trigger.Text, int(o.Position.X), int(o.Position.Y)-16)
+	})
+}
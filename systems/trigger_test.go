@@ -0,0 +1,44 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/events"
+	"github.com/AndriiPets/FishGame/factory"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
+
+func TestTrapTrigger(t *testing.T) {
+	tests := []struct {
+		name     string
+		velocity bool
+	}{
+		{"moving actor", true},
+		{"actor without velocity", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := ecs.NewECS(donburi.NewWorld())
+			factory.CreateClock(e, 1/float64(config.TickRate))
+			trap := factory.CreateTrap(e, 0, 0, 32, 32)
+
+			comps := []donburi.IComponentType{components.Health}
+			if tt.velocity {
+				comps = append(comps, components.Velocity)
+			}
+			actor := e.World.Entry(e.World.Create(comps...))
+			components.Health.Get(actor).Ammount = 10
+
+			OnTrapTrigger(e)(e.World, events.Trigger{Trigger: trap, Entry: actor})
+
+			health := components.Health.Get(actor)
+			if want := 10 - components.Trigger.Get(trap).Damage; health.Ammount != want || !health.Hit {
+				t.Fatalf("health %d hit %t, want %d and a hit", health.Ammount, health.Hit, want)
+			}
+		})
+	}
+}
@@ -2,16 +2,24 @@ package systems
 
 import (
 	"image/color"
+	"math"
 
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/events"
+	"github.com/AndriiPets/FishGame/factory"
 	dresolv "github.com/AndriiPets/FishGame/resolv"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/yohamta/donburi"
 	"github.com/yohamta/donburi/ecs"
+	dmath "github.com/yohamta/donburi/features/math"
 
 	"github.com/AndriiPets/FishGame/tags"
 )
 
+// particles thrown up by a crumbling block
+const debrisCount = 4
+
 func DrawWall(ecs *ecs.ECS, image *ebiten.Image) {
 	tags.Wall.Each(ecs.World, func(e *donburi.Entry) {
 		o := dresolv.GetObject(e)
@@ -19,3 +27,46 @@ func DrawWall(ecs *ecs.ECS, image *ebiten.Image) {
 		vector.DrawFilledRect(image, float32(o.Position.X), float32(o.Position.Y), float32(o.Size.X), float32(o.Size.Y), drawColor, false)
 	})
 }
+
+// damage_block wears a destructible block down, chipping off dust where it
+// was hit. Other blocks shrug it off.
+func damage_block(ecs *ecs.ECS, e *donburi.Entry, damage int, at dmath.Vec2) {
+	block := components.Block.Get(e)
+	if !block.Destructable || damage <= 0 || components.Health.Get(e).Dead {
+		return
+	}
+
+	components.Health.Get(e).DamageHealth(damage)
+	factory.CreateParticle(ecs, at.X, at.Y, factory.ParticleDust, 0, false, false)
+
+	if !block.Damaged {
+		block.Damaged = true
+		components.Animation.Get(e).Animation = block.Animation()
+	}
+}
+
+// OnBlockDestroyed crumbles a destroyed block into debris, it stops blocking
+// bullets right away and its cell opens up for pathing.
+func OnBlockDestroyed(ecs *ecs.ECS) func(w donburi.World, event events.Death) {
+	return func(w donburi.World, event events.Death) {
+		e := event.Entry
+		if !e.Valid() || !e.HasComponent(components.Block) {
+			return
+		}
+
+		o := dresolv.GetObject(e)
+		pf := components.PathFinder.Get(components.PathFinder.MustFirst(w))
+		pf.SetWalkable(pf.CoordToGrid(o.Position.X, o.Position.Y), true)
+
+		rng := GetRandom(ecs)
+		for i := 0; i < debrisCount; i++ {
+			x := o.Position.X + rng.Float64()*o.Size.X
+			y := o.Position.Y + rng.Float64()*o.Size.Y
+			factory.CreateParticle(ecs, x, y, factory.ParticleDebris, rng.Float64()*2*math.Pi, false, false)
+		}
+
+		dresolv.Remove(components.Space.MustFirst(w), e)
+		e.RemoveComponent(components.Collider)
+		components.Despawnable.Get(e).DespawnRequest = true
+	}
+}
@@ -0,0 +1,92 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/events"
+	"github.com/AndriiPets/FishGame/factory"
+	dresolv "github.com/AndriiPets/FishGame/resolv"
+	"github.com/AndriiPets/FishGame/utils"
+	"github.com/quasilyte/pathing"
+	"github.com/solarlune/resolv"
+	"github.com/yohamta/donburi"
+	dmath "github.com/yohamta/donburi/features/math"
+)
+
+func TestDamageBlock(t *testing.T) {
+	tests := []struct {
+		name         string
+		destructible bool
+		damage       int
+		health       int
+		damaged      bool
+	}{
+		{"destructible wall", true, 1, 2, true},
+		{"no damage", true, 0, 3, false},
+		{"plain wall", false, 1, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, space := blastWorld(t)
+			obj := resolv.NewObject(64, 64, 32, 32)
+			wall := factory.CreateWall(e, obj, components.BlockWall)
+			if tt.destructible {
+				wall = factory.CreateDestructibleWall(e, obj)
+			}
+			dresolv.Add(space, wall)
+
+			damage_block(e, wall, tt.damage, dmath.NewVec2(64, 80))
+
+			if tt.destructible {
+				if got := components.Health.Get(wall).Ammount; got != tt.health {
+					t.Errorf("health %d, want %d", got, tt.health)
+				}
+			}
+			if got := components.Block.Get(wall).Damaged; got != tt.damaged {
+				t.Errorf("damaged %t, want %t", got, tt.damaged)
+			}
+		})
+	}
+}
+
+func TestBlockDestroyed(t *testing.T) {
+	cell := pathing.GridCoord{X: 2, Y: 2}
+
+	tests := []struct {
+		name    string
+		block   bool //the dead entry is a wall block
+		removed bool
+	}{
+		{"wall crumbles", true, true},
+		{"actors are left alone", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, space := blastWorld(t)
+			pf := components.PathFinder.Get(components.PathFinder.MustFirst(e.World))
+			pf.SetWalkable(cell, false)
+
+			var dead *donburi.Entry
+			if tt.block {
+				dead = factory.CreateDestructibleWall(e, resolv.NewObject(64, 64, 32, 32))
+				dresolv.Add(space, dead)
+			} else {
+				dead = testActor(e, space, 72, 72, dmath.Vec2{}, 0)
+			}
+
+			OnBlockDestroyed(e)(e.World, events.Death{Entry: dead})
+
+			open := pf.Grid.GetCellTile(cell) == utils.TileFloor
+			gone := len(components.Space.Get(space).CheckCells(cell.X, cell.Y, 1, 1)) == 0
+			if open != tt.removed || gone != tt.removed {
+				t.Fatalf("cell open %t and object gone %t, want %t", open, gone, tt.removed)
+			}
+			if tt.removed && (dead.HasComponent(components.Collider) || !components.Despawnable.Get(dead).DespawnRequest) {
+				t.Fatalf("block still has a collider or is not despawning")
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package systems
+
+import (
+	"math"
+
+	"github.com/AndriiPets/FishGame/components"
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/factory"
+	dresolv "github.com/AndriiPets/FishGame/resolv"
+	"github.com/AndriiPets/FishGame/resources"
+	"github.com/solarlune/resolv"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	dmath "github.com/yohamta/donburi/features/math"
+)
+
+// fireHitscan casts one ray per pellet and damages the first hostile actor or
+// wall in its way, leaving a tracer from the muzzle to where the ray stopped.
+func fireHitscan(e *donburi.Entry, ecs *ecs.ECS) {
+	shooter := components.Shooter.Get(e)
+	attackVec := components.AttackVector.Get(e).Vec
+	weaponData := resources.WeaponMap[shooter.Type]
+	rng := GetRandom(ecs)
+	clock := GetClock(ecs)
+
+	spread := weaponData.Spread * math.Pi / 180
+	baseAngle := math.Atan2(attackVec.Y, attackVec.X)
+	muzzle := shooter.HolderPosition.Add(attackVec.MulScalar(24))
+
+	for i := 0; i < weaponData.Pellets; i++ {
+		angle := baseAngle
+		if spread > 0 {
+			angle += (rng.Float64() - 0.5) * spread
+		}
+		dir := dmath.NewVec2(math.Cos(angle), math.Sin(angle))
+
+		end, target := raycast(ecs, e, shooter.HolderPosition, dir, weaponData.Range, weaponData.FriendlyFire)
+		if target != nil && target.HasComponent(components.Block) {
+			damage_block(ecs, target, weaponData.Damage, end)
+		} else if target != nil {
+			apply_hit(ecs, target, dir, weaponData.Knockback)
+			components.Health.Get(target).DamageHealth(weaponData.Damage)
+		}
+
+		factory.CreateTracer(ecs, muzzle, end, clock.Tick)
+	}
+}
+
+// fireMelee hits every hostile actor whose center lies inside the swing arc.
+func fireMelee(e *donburi.Entry, ecs *ecs.ECS) {
+	shooter := components.Shooter.Get(e)
+	attackVec := components.AttackVector.Get(e).Vec.Normalized()
+	weaponData := resources.WeaponMap[shooter.Type]
+	faction := components.FactionOf(e)
+	halfArc := weaponData.Arc * math.Pi / 360
+
+	space := components.Space.Get(components.Space.MustFirst(ecs.World))
+
+	//only the objects in the cells around the swing are looked at, the ones
+	//spanning several cells show up more than once
+	reach := weaponData.Range + float64(config.BlockSize)
+	from := shooter.HolderPosition
+	hit := map[donburi.Entity]bool{}
+	for _, obj := range space.CheckWorld(from.X-reach, from.Y-reach, reach*2+float64(config.BlockSize), reach*2+float64(config.BlockSize)) {
+		target, ok := actor_hit_by(ecs, e, faction, weaponData.FriendlyFire, obj)
+		if !ok || hit[target.Entity()] {
+			continue
+		}
+
+		center := dmath.NewVec2(obj.Position.X+obj.Size.X/2, obj.Position.Y+obj.Size.Y/2)
+		toTarget := center.Sub(from)
+
+		//targets are reached as soon as their edge is in range
+		if toTarget.Magnitude() > weaponData.Range+math.Max(obj.Size.X, obj.Size.Y)/2 {
+			continue
+		}
+
+		dir := toTarget.Normalized()
+		if math.Acos(math.Max(-1, math.Min(1, dir.Dot(&attackVec)))) > halfArc {
+			continue
+		}
+
+		hit[target.Entity()] = true
+		apply_hit(ecs, target, dir, weaponData.Knockback)
+		components.Health.Get(target).DamageHealth(weaponData.Damage)
+	}
+}
+
+// actor_hit_by returns the living actor a collision object belongs to when the
+// owner is allowed to damage it.
+func actor_hit_by(ecs *ecs.ECS, owner *donburi.Entry, faction components.Faction, friendlyFire bool, obj *resolv.Object) (*donburi.Entry, bool) {
+	e, ok := dresolv.Entry(ecs.World, obj)
+	if !ok || e.HasComponent(components.Block) || !e.HasComponent(components.Health) || components.Health.Get(e).Dead {
+		return nil, false
+	}
+	if !components.CanDamage(owner.Entity(), faction, friendlyFire, e) {
+		return nil, false
+	}
+
+	return e, true
+}
+
+// raycast walks the space cells along the ray and returns where it stopped and
+// what on, either a wall or the first actor the owner is allowed to damage.
+func raycast(ecs *ecs.ECS, owner *donburi.Entry, from, dir dmath.Vec2, length float64, friendlyFire bool) (dmath.Vec2, *donburi.Entry) {
+	space := components.Space.Get(components.Space.MustFirst(ecs.World))
+	faction := components.FactionOf(owner)
+	ray := dir.Normalized().MulScalar(length)
+	to := from.Add(ray)
+
+	startX, startY := clampCell(space, from)
+	endX, endY := clampCell(space, to)
+
+	nearest := 1.0
+	var hit *donburi.Entry
+	seen := map[*resolv.Object]bool{}
+
+	for _, cell := range space.CellsInLine(startX, startY, endX, endY) {
+		for _, obj := range cell.Objects {
+			if seen[obj] {
+				continue
+			}
+			seen[obj] = true
+
+			target, isActor := actor_hit_by(ecs, owner, faction, friendlyFire, obj)
+			if !isActor && !obj.HasTags(components.LayerWall.Tag()) {
+				continue
+			}
+			if !isActor {
+				target, _ = dresolv.Entry(ecs.World, obj)
+			}
+
+			t, _, ok := sweep_rect(from, ray, obj.Position.X, obj.Position.Y, obj.Size.X, obj.Size.Y)
+			if ok && t < nearest {
+				nearest = t
+				hit = target
+			}
+		}
+	}
+
+	return from.Add(ray.MulScalar(nearest)), hit
+}
+
+func clampCell(space *resolv.Space, pos dmath.Vec2) (int, int) {
+	x, y := space.WorldToSpace(pos.X, pos.Y)
+	x = int(math.Max(0, math.Min(float64(space.Width()-1), float64(x))))
+	y = int(math.Max(0, math.Min(float64(space.Height()-1), float64(y))))
+	return x, y
+}
+
+// chargeAmount returns how charged a held weapon is, from 0 to 1.
+func chargeAmount(ecs *ecs.ECS, shooter *components.ShooterData) float64 {
+	weaponData := resources.WeaponMap[shooter.Type]
+	return math.Min(GetClock(ecs).Since(shooter.ChargeStart)/weaponData.ChargeTime, 1)
+}
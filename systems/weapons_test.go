@@ -0,0 +1,38 @@
+package systems
+
+import (
+	"testing"
+
+	dmath "github.com/yohamta/donburi/features/math"
+)
+
+func TestRaycast(t *testing.T) {
+	tests := []struct {
+		name         string
+		friendlyFire bool
+		actor        bool //the ray stops on the ally instead of the wall
+		stop         float64
+	}{
+		{"passes allies", false, false, 160},
+		{"friendly fire hits allies", true, true, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, space := hookWorld()
+			owner := testActor(e, space, 0, 100, dmath.Vec2{}, 0)
+			ally := testActor(e, space, 100, 100, dmath.Vec2{}, 0)
+			wall := testWall(e, space, 160, 64, 32, 96)
+
+			at, hit := raycast(e, owner, dmath.NewVec2(20, 108), dmath.NewVec2(1, 0), 300, tt.friendlyFire)
+
+			want := wall
+			if tt.actor {
+				want = ally
+			}
+			if hit == nil || hit.Entity() != want.Entity() || !near(at.X, tt.stop) || !near(at.Y, 108) {
+				t.Fatalf("stopped at %v on %v, want %.0f on %v", at, hit, tt.stop, want)
+			}
+		})
+	}
+}
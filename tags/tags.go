@@ -10,4 +10,6 @@ var (
 	WeaponSprite = donburi.NewTag().SetName("WeaponSprite")
 	Enemy        = donburi.NewTag().SetName("enemy")
 	Particle     = donburi.NewTag().SetName("particle")
+	Pickup       = donburi.NewTag().SetName("pickup")
+	Exit         = donburi.NewTag().SetName("exit")
 )
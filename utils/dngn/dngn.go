@@ -21,10 +21,13 @@ import (
 // Data is the core underlying data structure representing the dungeon. It's a 2D array of runes.
 // Seed is the seed of the Layout to use when doing random generation using the Generate* functions below. By default, the seed is
 // the lowest possible negative number (math.MinInt64), and so will use the time to set the seed.
+// Each Layout owns its random source, so generating one Layout doesn't disturb any other.
 type Layout struct {
 	Width, Height int
 	Data          [][]rune
 	Seed          int64
+
+	rng *rand.Rand
 }
 
 // NewLayout returns a new Layout with the specified width and height.
@@ -43,10 +46,27 @@ func NewLayout(width, height int) *Layout {
 
 }
 
+// seedRandom resets the random source of the Layout from its Seed.
+func (layout *Layout) seedRandom() {
+	seed := layout.Seed
+	if seed == math.MinInt64 {
+		seed = time.Now().UnixNano()
+	}
+	layout.rng = rand.New(rand.NewSource(seed))
+}
+
+// random returns the random source of the Layout, seeding it first if needed.
+func (layout *Layout) random() *rand.Rand {
+	if layout.rng == nil {
+		layout.seedRandom()
+	}
+	return layout.rng
+}
+
 // NewLayoutFromRuneArrays creates a new Layout with the data contained in the provided rune arrays.
 func NewLayoutFromRuneArrays(arrays [][]rune) *Layout {
 
-	r := &Layout{Width: len(arrays[0]), Height: len(arrays)}
+	r := &Layout{Width: len(arrays[0]), Height: len(arrays), Seed: math.MinInt64}
 	r.Data = [][]rune{}
 	for y := 0; y < len(arrays); y++ {
 		r.Data = append(r.Data, []rune{})
@@ -229,14 +249,14 @@ func (layout *Layout) GenerateBSP(bspOptions BSPOptions) []*BSPRoom {
 
 	subSplit := func(parent *BSPRoom) (*BSPRoom, *BSPRoom, bool) {
 
-		vertical := rand.Float32() >= 0.5
+		vertical := layout.random().Float32() >= 0.5
 		if parent.W > parent.H*2 {
 			vertical = true
 		} else if parent.H > parent.W*2 {
 			vertical = false
 		}
 
-		splitPercentage := 0.2 + rand.Float32()*0.6
+		splitPercentage := 0.2 + layout.random().Float32()*0.6
 
 		if vertical {
 
@@ -284,11 +304,7 @@ func (layout *Layout) GenerateBSP(bspOptions BSPOptions) []*BSPRoom {
 		NewBSPRoom(0, 0, layout.Width, layout.Height),
 	}
 
-	if layout.Seed > math.MinInt64 {
-		rand.Seed(layout.Seed)
-	} else {
-		rand.Seed(time.Now().UnixNano())
-	}
+	layout.seedRandom()
 
 	splitCount := 0
 
@@ -301,9 +317,9 @@ func (layout *Layout) GenerateBSP(bspOptions BSPOptions) []*BSPRoom {
 			return rooms[i].MinSize() > rooms[j].MinSize()
 		})
 
-		splitChoice := rooms[rand.Intn(len(rooms))]
+		splitChoice := rooms[layout.random().Intn(len(rooms))]
 
-		if rand.Float32() >= 0.2 {
+		if layout.random().Float32() >= 0.2 {
 			splitChoice = rooms[0] // Try to split the biggest rooms first
 		}
 
@@ -347,7 +363,7 @@ func (layout *Layout) GenerateBSP(bspOptions BSPOptions) []*BSPRoom {
 
 		spawnOptions := []int{0, 1, 2}
 
-		spawnChoice := spawnOptions[rand.Intn(len(spawnOptions))]
+		spawnChoice := spawnOptions[layout.random().Intn(len(spawnOptions))]
 
 		// Rooms on the border must generate a doorway that works for them
 		if subroom.X == 0 || subroom.Y == 0 {
@@ -370,19 +386,24 @@ func (layout *Layout) GenerateBSP(bspOptions BSPOptions) []*BSPRoom {
 
 				}
 
-				doorway := possibleExits[rand.Intn(len(possibleExits))]
+				// A wall without room for a doorway leaves the room unconnected on this side
+				if len(possibleExits) > 0 {
+
+					doorway := possibleExits[layout.random().Intn(len(possibleExits))]
+
+					layout.Set(doorway.X, doorway.Y, bspOptions.DoorValue)
 
-				layout.Set(doorway.X, doorway.Y, bspOptions.DoorValue)
+					doorRect := image.Rect(doorway.X, doorway.Y-1, doorway.X+1, doorway.Y)
 
-				doorRect := image.Rect(doorway.X, doorway.Y-1, doorway.X+1, doorway.Y)
+					for _, other := range rooms {
 
-				for _, other := range rooms {
+						otherRect := image.Rect(other.X, other.Y, other.X+other.W, other.Y+other.H)
 
-					otherRect := image.Rect(other.X, other.Y, other.X+other.W, other.Y+other.H)
+						if otherRect.Overlaps(doorRect) {
+							other.Connected = append(other.Connected, subroom)
+							subroom.Connected = append(subroom.Connected, other)
+						}
 
-					if otherRect.Overlaps(doorRect) {
-						other.Connected = append(other.Connected, subroom)
-						subroom.Connected = append(subroom.Connected, other)
 					}
 
 				}
@@ -409,16 +430,20 @@ func (layout *Layout) GenerateBSP(bspOptions BSPOptions) []*BSPRoom {
 
 				}
 
-				doorway := possibleExits[rand.Intn(len(possibleExits))]
+				if len(possibleExits) > 0 {
 
-				layout.Set(doorway.X, doorway.Y, bspOptions.DoorValue)
+					doorway := possibleExits[layout.random().Intn(len(possibleExits))]
 
-				for _, other := range rooms {
+					layout.Set(doorway.X, doorway.Y, bspOptions.DoorValue)
+
+					for _, other := range rooms {
+
+						otherRect := image.Rect(other.X, other.Y, other.X+other.W, other.Y+other.H)
+						if otherRect.Overlaps(image.Rect(doorway.X-1, doorway.Y, doorway.X, doorway.Y+1)) {
+							other.Connected = append(other.Connected, subroom)
+							subroom.Connected = append(subroom.Connected, other)
+						}
 
-					otherRect := image.Rect(other.X, other.Y, other.X+other.W, other.Y+other.H)
-					if otherRect.Overlaps(image.Rect(doorway.X-1, doorway.Y, doorway.X, doorway.Y+1)) {
-						other.Connected = append(other.Connected, subroom)
-						subroom.Connected = append(subroom.Connected, other)
 					}
 
 				}
@@ -441,25 +466,21 @@ func (layout *Layout) GenerateRandomRooms(emptyRune rune, wallRune rune, roomCou
 
 	layout.Select().Fill(wallRune)
 
-	if layout.Seed > math.MinInt64 {
-		rand.Seed(layout.Seed)
-	} else {
-		rand.Seed(time.Now().UnixNano())
-	}
+	layout.seedRandom()
 
 	roomPositions := make([][]int, 0)
 
 	for i := 0; i < roomCount; i++ {
 
-		// roomSize := float64(2 + rand.Intn(2))
+		// roomSize := float64(2 + layout.random().Intn(2))
 
-		sx := rand.Intn(layout.Width)
-		sy := rand.Intn(layout.Height)
+		sx := layout.random().Intn(layout.Width)
+		sy := layout.random().Intn(layout.Height)
 
 		roomPositions = append(roomPositions, []int{sx, sy})
 
-		roomW := roomMinWidth + rand.Intn(roomMaxWidth-roomMinWidth)
-		roomH := roomMinHeight + rand.Intn(roomMaxHeight-roomMinHeight)
+		roomW := roomMinWidth + layout.random().Intn(roomMaxWidth-roomMinWidth+1)
+		roomH := roomMinHeight + layout.random().Intn(roomMaxHeight-roomMinHeight+1)
 
 		drawRoom := func(x, y int) bool {
 			dx := int(math.Abs(float64(sx) - float64(x)))
@@ -508,14 +529,10 @@ func (layout *Layout) GenerateDrunkWalk(emptyRune rune, wallRune rune, percentag
 	selection := layout.Select()
 	selection.Fill(wallRune)
 
-	if layout.Seed > math.MinInt64 {
-		rand.Seed(layout.Seed)
-	} else {
-		rand.Seed(time.Now().UnixNano())
-	}
+	layout.seedRandom()
 
-	sx := rand.Intn(layout.Width)
-	sy := rand.Intn(layout.Height)
+	sx := layout.random().Intn(layout.Width)
+	sy := layout.random().Intn(layout.Height)
 
 	startX := sx
 	startY := sy
@@ -526,14 +543,27 @@ func (layout *Layout) GenerateDrunkWalk(emptyRune rune, wallRune rune, percentag
 
 	for true {
 
-		cell := selection.FilterByArea(sx, sy, 2, 2)
+		// Carve the 2x2 cell directly; filtering the whole selection every step is slow on bigger layouts
+		carve := false
+
+		for y := sy; y < sy+2 && y < layout.Height; y++ {
+			for x := sx; x < sx+2 && x < layout.Width; x++ {
+				if layout.Get(x, y) == wallRune {
+					carve = true
+				}
+			}
+		}
 
-		if cell.ContainsChar(wallRune) {
-			cell.Fill(emptyRune)
+		if carve {
+			for y := sy; y < sy+2 && y < layout.Height; y++ {
+				for x := sx; x < sx+2 && x < layout.Width; x++ {
+					layout.Set(x, y, emptyRune)
+				}
+			}
 			fillCount += 4
 		}
 
-		dir := rand.Intn(4)
+		dir := layout.random().Intn(4)
 
 		if dir == 0 {
 			sx++
@@ -923,13 +953,16 @@ func (selection Selection) None() Selection {
 // FilterByPercentage selects the provided percentage (from 0 - 1) of the cells curently in the Selection.
 func (selection Selection) FilterByPercentage(percentage float32) Selection {
 
-	return selection.FilterBy(func(x, y int) bool {
+	newSelection := selection.None()
 
-		if rand.Float32() <= percentage {
-			return true
+	// Cells are visited in order so that the result is reproducible for a given seed
+	for _, c := range selection.sortedCells() {
+		if selection.Layout.random().Float32() <= percentage {
+			newSelection.Cells[c] = true
 		}
-		return false
-	})
+	}
+
+	return newSelection
 
 }
 
@@ -1047,7 +1080,7 @@ func (selection Selection) Select(num int) []Position {
 
 	cells := []Position{}
 
-	for cell := range selection.Cells {
+	for _, cell := range selection.sortedCells() {
 		cells = append(cells, cell)
 		if len(cells) >= num {
 			return cells
@@ -1058,6 +1091,26 @@ func (selection Selection) Select(num int) []Position {
 
 }
 
+// sortedCells returns the cells of the Selection ordered by row, then column.
+func (selection Selection) sortedCells() []Position {
+
+	cells := make([]Position, 0, len(selection.Cells))
+
+	for cell := range selection.Cells {
+		cells = append(cells, cell)
+	}
+
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Y != cells[j].Y {
+			return cells[i].Y < cells[j].Y
+		}
+		return cells[i].X < cells[j].X
+	})
+
+	return cells
+
+}
+
 // Expand expands the selection outwards by the distance value provided. Diagonal indicates if the expansion should happen
 // diagonally as well, or just on the cardinal 4 directions. If a negative value is given for distance, it shrinks the selection.
 func (selection Selection) Expand(distance int, diagonal bool) Selection {
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"math/rand"
+
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/utils/dngn"
+)
+
+// EnemyWeight is an enemy type the encounter placer can pick.
+type EnemyWeight struct {
+	Type   string
+	Weight int //relative chance of being picked
+	Cost   int //share of the floor budget it takes, 1 when unset
+}
+
+func (e EnemyWeight) cost() int {
+	if e.Cost <= 0 {
+		return 1
+	}
+	return e.Cost
+}
+
+// encounterRoom is an area enemies are placed in, hops is how many doors
+// separate it from the start room.
+type encounterRoom struct {
+	X, Y, W, H int
+	Hops       int
+}
+
+// placeEncounters spends the enemy budget on spawns spread over the rooms.
+// Rooms farther from the start are picked more often and tiles close to the
+// start are never used. Returns the tiles taken so nothing else lands on them.
+func (w *World) placeEncounters(rooms []encounterRoom, start dngn.Position, opts MapOptions) map[dngn.Position]bool {
+	rng := featureRand(opts.Seed, saltEncounters)
+	occupied := map[dngn.Position]bool{}
+
+	//free floor of each room
+	tiles := make([][]dngn.Position, len(rooms))
+	for i, room := range rooms {
+		if room.Hops <= 0 {
+			continue
+		}
+		for y := room.Y; y < room.Y+room.H; y++ {
+			for x := room.X; x < room.X+room.W; x++ {
+				pos := dngn.Position{X: x, Y: y}
+				if w.Map.Get(x, y) == ' ' && start.DistanceTo(pos) > enemySafeRadius {
+					tiles[i] = append(tiles[i], pos)
+				}
+			}
+		}
+	}
+
+	budget := opts.EnemyBudget
+
+	for budget > 0 {
+		enemy, ok := pickEnemy(rng, opts.EnemyTypes, budget)
+		if !ok {
+			break
+		}
+
+		room := pickRoom(rng, rooms, tiles)
+		if room < 0 {
+			break
+		}
+
+		//take a random tile out of the room
+		free := tiles[room]
+		i := rng.Intn(len(free))
+		pos := free[i]
+		free[i] = free[len(free)-1]
+		tiles[room] = free[:len(free)-1]
+
+		//random rooms overlap, a tile can show up in more than one
+		if occupied[pos] {
+			continue
+		}
+		occupied[pos] = true
+
+		w.Spawns = append(w.Spawns, Spawn{
+			Kind: SpawnEnemy,
+			Type: enemy.Type,
+			X:    float64(pos.X * config.BlockSize),
+			Y:    float64(pos.Y * config.BlockSize),
+		})
+		budget -= enemy.cost()
+	}
+
+	return occupied
+}
+
+// pickEnemy rolls an enemy type the remaining budget can pay for.
+func pickEnemy(rng *rand.Rand, types []EnemyWeight, budget int) (EnemyWeight, bool) {
+	total := 0
+	for _, t := range types {
+		if t.cost() <= budget {
+			total += t.Weight
+		}
+	}
+	if total <= 0 {
+		return EnemyWeight{}, false
+	}
+
+	roll := rng.Intn(total)
+	for _, t := range types {
+		if t.cost() > budget {
+			continue
+		}
+		if roll < t.Weight {
+			return t, true
+		}
+		roll -= t.Weight
+	}
+
+	return EnemyWeight{}, false
+}
+
+// pickRoom rolls a room with free floor left, weighted by its distance from the start.
+func pickRoom(rng *rand.Rand, rooms []encounterRoom, tiles [][]dngn.Position) int {
+	total := 0
+	for i, room := range rooms {
+		if len(tiles[i]) > 0 {
+			total += room.Hops
+		}
+	}
+	if total <= 0 {
+		return -1
+	}
+
+	roll := rng.Intn(total)
+	for i, room := range rooms {
+		if len(tiles[i]) == 0 {
+			continue
+		}
+		if roll < room.Hops {
+			return i
+		}
+		roll -= room.Hops
+	}
+
+	return -1
+}
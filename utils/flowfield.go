@@ -0,0 +1,98 @@
+package utils
+
+import (
+	path "github.com/quasilyte/pathing"
+)
+
+// FlowField holds the number of steps from every cell to a goal cell, so any
+// number of agents can walk towards the goal by stepping downhill.
+type FlowField struct {
+	Goal  path.GridCoord
+	Valid bool
+
+	dist  []int32 //-1 where the goal can't be reached
+	queue []path.GridCoord
+	cols  int
+	rows  int
+}
+
+var neighbours = [4]path.Direction{path.DirRight, path.DirDown, path.DirLeft, path.DirUp}
+
+// UpdateFlow points the flow field at the cell of the given position. The field
+// is only recomputed when that cell changes.
+func (p *PathFinder) UpdateFlow(x, y float64) *FlowField {
+	goal := p.CoordToGrid(x, y)
+	if p.Flow.Valid && p.Flow.Goal == goal {
+		return &p.Flow
+	}
+
+	p.Flow.build(p, goal)
+	return &p.Flow
+}
+
+func (f *FlowField) build(p *PathFinder, goal path.GridCoord) {
+	f.cols, f.rows = p.Grid.NumCols(), p.Grid.NumRows()
+	if len(f.dist) != f.cols*f.rows {
+		f.dist = make([]int32, f.cols*f.rows)
+	}
+	for i := range f.dist {
+		f.dist[i] = -1
+	}
+
+	f.Goal = goal
+	f.Valid = true
+	if !f.inside(goal) {
+		return
+	}
+
+	//breadth first from the goal over every walkable cell
+	f.dist[f.index(goal)] = 0
+	f.queue = append(f.queue[:0], goal)
+	for i := 0; i < len(f.queue); i++ {
+		cell := f.queue[i]
+		d := f.dist[f.index(cell)]
+
+		for _, dir := range neighbours {
+			next := cell.Move(dir)
+			if !f.inside(next) || f.dist[f.index(next)] != -1 || p.Grid.GetCellCost(next, p.Layers) == 0 {
+				continue
+			}
+			f.dist[f.index(next)] = d + 1
+			f.queue = append(f.queue, next)
+		}
+	}
+}
+
+// Dist is the number of steps from the cell to the goal, -1 when there is no way.
+func (f *FlowField) Dist(c path.GridCoord) int {
+	if !f.inside(c) {
+		return -1
+	}
+	return int(f.dist[f.index(c)])
+}
+
+// Next returns the neighbour of the cell that is closest to the goal, it fails
+// at the goal and where the goal can't be reached.
+func (f *FlowField) Next(c path.GridCoord) (path.GridCoord, bool) {
+	best, bestDist := c, f.Dist(c)
+	if bestDist <= 0 {
+		return c, false
+	}
+
+	for _, dir := range neighbours {
+		next := c.Move(dir)
+		if d := f.Dist(next); d >= 0 && d < bestDist {
+			best, bestDist = next, d
+		}
+	}
+
+	return best, best != c
+}
+
+func (f *FlowField) inside(c path.GridCoord) bool {
+	return c.X >= 0 && c.Y >= 0 && c.X < f.cols && c.Y < f.rows
+}
+
+func (f *FlowField) index(c path.GridCoord) int {
+	return c.Y*f.cols + c.X
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/quasilyte/pathing"
+)
+
+// cells of the flow field an agent turns into a route, as the enemy ai does
+const benchLookahead = 12
+
+// benchAgents is how many enemies ask for a path every tick.
+const benchAgents = 128
+
+// benchLevel generates the seed 1 level and picks agent starts and goals on
+// its floor.
+func benchLevel(b *testing.B) (*PathFinder, []pathing.GridCoord, []pathing.GridCoord) {
+	world := NewWorldMap()
+	opts := DefaultMapOptions()
+	opts.Seed = 1
+	if err := world.GenerateMap(opts); err != nil {
+		b.Fatal(err)
+	}
+
+	var floor []pathing.GridCoord
+	for y, row := range world.Map.Data {
+		for x, val := range row {
+			if val != 'x' {
+				floor = append(floor, pathing.GridCoord{X: x, Y: y})
+			}
+		}
+	}
+
+	pf := NewPathFinder()
+	pf.GenerateLayout(world.Map.Data, 'x')
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	starts := make([]pathing.GridCoord, benchAgents)
+	for i := range starts {
+		starts[i] = floor[rng.Intn(len(floor))]
+	}
+	goals := make([]pathing.GridCoord, 64)
+	for i := range goals {
+		goals[i] = floor[rng.Intn(len(floor))]
+	}
+
+	return pf, starts, goals
+}
+
+func BenchmarkGreedyBFS(b *testing.B) {
+	pf, starts, goals := benchLevel(b)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		goal := goals[i%len(goals)]
+		for _, s := range starts {
+			pf.BFS.BuildPath(pf.Grid, s, goal, pf.Layers)
+		}
+	}
+}
+
+func BenchmarkFlowField(b *testing.B) {
+	pf, starts, goals := benchLevel(b)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		//the player changes cell every tick, the worst case for the field
+		gx, gy := pf.Grid.CoordToPos(goals[i%len(goals)])
+		pf.UpdateFlow(gx, gy)
+
+		for _, s := range starts {
+			cell := s
+			for step := 0; step < benchLookahead; step++ {
+				next, ok := pf.Flow.Next(cell)
+				if !ok {
+					break
+				}
+				cell = next
+			}
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"math/rand"
 
 	"github.com/AndriiPets/FishGame/config"
 	"github.com/AndriiPets/FishGame/utils/dngn"
@@ -15,8 +17,84 @@ const (
 	RandomRooms GenerationType = "random"
 )
 
+var ErrDegenerateMap = errors.New("degenerate map")
+
+type MapOptions struct {
+	Type                GenerationType
+	Seed                int64
+	RoomSize            int           //minimum room size for BSP, maximum room size for random rooms
+	SplitCount          int           //how many times BSP splits the map
+	HopLimit            int           //rooms farther than this many doors from the start are walled off
+	EnemyBudget         int           //total cost of the enemies placed on the floor
+	EnemyTypes          []EnemyWeight //enemy types the budget is spent on
+	PickupDensity       float64       //chance of a weapon pickup on each floor tile
+	TrapDensity         float64       //chance of a spike trap on each floor tile
+	DestructibleDensity float64       //chance of an inner wall next to the floor being destructible
+	BarrelDensity       float64       //chance of an explosive barrel on each floor tile along a wall
+}
+
+func DefaultMapOptions() MapOptions {
+	return MapOptions{
+		Type:                BSP,
+		RoomSize:            5,
+		SplitCount:          100,
+		HopLimit:            4,
+		EnemyBudget:         8,
+		EnemyTypes:          []EnemyWeight{{Type: "orc", Weight: 1}},
+		PickupDensity:       0.002,
+		TrapDensity:         0.002,
+		DestructibleDensity: 0.05,
+		BarrelDensity:       0.01,
+	}
+}
+
+func (o MapOptions) validate() error {
+	switch o.Type {
+	case BSP, DrunkWalk, RandomRooms:
+	default:
+		return fmt.Errorf("unknown generation type: %q", o.Type)
+	}
+
+	if o.RoomSize < 3 {
+		return fmt.Errorf("room size must be at least 3, got %d", o.RoomSize)
+	}
+	if o.HopLimit < 0 {
+		return fmt.Errorf("hop limit must not be negative, got %d", o.HopLimit)
+	}
+	if o.EnemyBudget < 0 {
+		return fmt.Errorf("enemy budget must not be negative, got %d", o.EnemyBudget)
+	}
+	for _, t := range o.EnemyTypes {
+		if t.Type == "" {
+			return errors.New("enemy type without a name")
+		}
+		if t.Weight < 0 || t.Cost < 0 {
+			return fmt.Errorf("enemy type %s: weight and cost must not be negative", t.Type)
+		}
+	}
+	if o.PickupDensity < 0 || o.PickupDensity > 1 {
+		return fmt.Errorf("pickup density must be between 0 and 1, got %f", o.PickupDensity)
+	}
+	if o.TrapDensity < 0 || o.TrapDensity > 1 {
+		return fmt.Errorf("trap density must be between 0 and 1, got %f", o.TrapDensity)
+	}
+	if o.DestructibleDensity < 0 || o.DestructibleDensity > 1 {
+		return fmt.Errorf("destructible wall density must be between 0 and 1, got %f", o.DestructibleDensity)
+	}
+	if o.BarrelDensity < 0 || o.BarrelDensity > 1 {
+		return fmt.Errorf("barrel density must be between 0 and 1, got %f", o.BarrelDensity)
+	}
+
+	return nil
+}
+
+// enemies are not placed this close to the player start
+const enemySafeRadius = 6
+
 type World struct {
-	Map *dngn.Layout
+	Map          *dngn.Layout
+	Spawns       []Spawn                //entities placed in addition to the runes in Map
+	Destructible map[dngn.Position]bool //walls in Map that can be shot down
 }
 
 func NewWorldMap() *World {
@@ -27,18 +105,29 @@ func NewWorldMap() *World {
 	return world
 }
 
-func (w *World) GenerateMap(genType GenerationType) {
+func (w *World) GenerateMap(opts MapOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	w.Map.Seed = opts.Seed
 	mapSelection := w.Map.Select()
 
-	switch genType {
+	var start dngn.Position
+	var rooms []encounterRoom
+
+	switch opts.Type {
 	case BSP:
 		bspOptions := dngn.NewDefaultBSPOptions()
-		bspOptions.SplitCount = 100
-		bspOptions.MinimumRoomSize = 5
+		bspOptions.SplitCount = opts.SplitCount
+		bspOptions.MinimumRoomSize = opts.RoomSize
 
 		bspRooms := w.Map.GenerateBSP(bspOptions)
+		if len(bspRooms) == 0 {
+			return fmt.Errorf("%w: bsp generated no rooms (seed %d)", ErrDegenerateMap, opts.Seed)
+		}
 
-		start := bspRooms[0]
+		startRoom := bspRooms[0]
 
 		for _, subroom := range bspRooms {
 
@@ -51,41 +140,61 @@ func (w *World) GenerateMap(genType GenerationType) {
 				subroomCenter.X < center.X+margin &&
 				subroomCenter.Y > center.Y-margin &&
 				subroomCenter.Y < center.Y+margin {
-				start = subroom
+				startRoom = subroom
 				break
 			}
 
 		}
 
+		connected := 0
+
 		for _, room := range bspRooms {
 
-			hops := room.CountHopsTo(start)
+			hops := room.CountHopsTo(startRoom)
 			//mapSelection.FilterByArea(room.X, room.Y, room.W+1, room.H+1).Fill('Z')
 
-			if hops < 0 || hops > 4 {
+			if hops < 0 || hops > opts.HopLimit {
 				// We're filtering out a little bit more on the width and height because the walls and doorways in GenerateBSP() are always on the top and left sides of each room.
 				// By adding the right and bottom as well, we can nuke any doors that led into rooms we're deleting.
 				mapSelection.FilterByArea(room.X, room.Y, room.W+1, room.H+1).Fill('x')
 				room.Disconnect()
+			} else if room != startRoom {
+				connected++
+				rooms = append(rooms, encounterRoom{X: room.X, Y: room.Y, W: room.W, H: room.H, Hops: hops})
 			}
 
 		}
 
-		player_pos := start.Center()
-		fmt.Println(w.Map.Get(player_pos.X, player_pos.Y))
-		w.Map.Set(player_pos.X, player_pos.Y, 'P')
-		//w.Map.Set(player_pos.X+5, player_pos.Y, 'e')
-		w.Map.Set(player_pos.X+1, player_pos.Y+1, 'e')
+		if connected == 0 && opts.HopLimit > 0 {
+			return fmt.Errorf("%w: start room has no reachable neighbours (seed %d)", ErrDegenerateMap, opts.Seed)
+		}
+
+		start = startRoom.Center()
 
 	case DrunkWalk:
 		startX, startY := w.Map.GenerateDrunkWalk(' ', 'x', 0.8)
-		w.Map.Set(startX, startY, 'P')
-		w.Map.Set(startX+1, startY+1, 'e')
+		start = dngn.Position{X: startX, Y: startY}
+
+		//caves have no rooms, the whole map is one
+		rooms = append(rooms, encounterRoom{W: w.Map.Width, H: w.Map.Height, Hops: 1})
 
 	case RandomRooms:
-		rooms := w.Map.GenerateRandomRooms(' ', 'x', 10, 3, 3, 5, 5, true)
-		start := rooms[0]
-		w.Map.Set(start[0], start[1], 'P')
+		positions := w.Map.GenerateRandomRooms(' ', 'x', 10, 3, 3, opts.RoomSize, opts.RoomSize, true)
+		if len(positions) == 0 {
+			return fmt.Errorf("%w: no random rooms were placed (seed %d)", ErrDegenerateMap, opts.Seed)
+		}
+		start = dngn.Position{X: positions[0][0], Y: positions[0][1]}
+
+		//rooms are chained in placement order and extend up to the room size around their position
+		for i, p := range positions[1:] {
+			rooms = append(rooms, encounterRoom{
+				X:    p[0] - opts.RoomSize + 1,
+				Y:    p[1] - opts.RoomSize + 1,
+				W:    opts.RoomSize*2 - 1,
+				H:    opts.RoomSize*2 - 1,
+				Hops: i + 1,
+			})
+		}
 
 		// This selects the ground tiles that are between walls to place doors randomly. This isn't really good, but it at least
 		// gets the idea across.
@@ -97,9 +206,146 @@ func (w *World) GenerateMap(genType GenerationType) {
 	// Fill the outer walls
 	mapSelection.Remove(mapSelection.FilterByArea(1, 1, w.Map.Width-2, w.Map.Height-2)).Fill('x')
 
+	if w.Map.Get(start.X, start.Y) != ' ' {
+		return fmt.Errorf("%w: player start %d,%d is not on the floor (seed %d)", ErrDegenerateMap, start.X, start.Y, opts.Seed)
+	}
+	w.Map.Set(start.X, start.Y, 'P')
+
+	// The exit to the next floor goes as far from the start as the layout allows
+	exit, ok := w.farthestFloor(start)
+	if !ok {
+		return fmt.Errorf("%w: no floor left for the exit (seed %d)", ErrDegenerateMap, opts.Seed)
+	}
+	w.Map.Set(exit.X, exit.Y, '>')
+
+	// Enemies are spawn records so each one can have its own type
+	occupied := w.placeEncounters(rooms, start, opts)
+	w.placeTraps(start, occupied, opts)
+	w.placeBarrels(start, occupied, opts)
+
+	// BSP rooms are walled, a room trigger locks their doorways during a fight.
+	// The top and left walls belong to the room.
+	if opts.Type == BSP {
+		for _, room := range rooms {
+			w.addRoom(room.X+1, room.Y+1, room.W-1, room.H-1)
+		}
+	}
+
+	// Weapon pickups anywhere on the floor
+	mapSelection.FilterByRune(' ').FilterBy(func(x, y int) bool {
+		return !occupied[dngn.Position{X: x, Y: y}]
+	}).FilterByPercentage(float32(opts.PickupDensity)).Fill('w')
+
 	// Add a different tile for an alternate floor
 	mapSelection.FilterByRune(' ').FilterByPercentage(0.1).Fill('.')
 	//mapSelection.FilterByRune(' ').FilterByPercentage(0.01).Fill('e')
 
-	fmt.Println(w.Map.DataToString())
+	// Some walls can be shot through, the outer ones always hold
+	w.markDestructible(opts)
+
+	return nil
+}
+
+// farthestFloor walks the map from start and returns the empty floor tile
+// reached last. Ties go to the first tile found so the result only depends on
+// the layout.
+func (w *World) farthestFloor(start dngn.Position) (dngn.Position, bool) {
+	visited := map[dngn.Position]bool{start: true}
+	queue := []dngn.Position{start}
+
+	var farthest dngn.Position
+	found := false
+
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+
+		if w.Map.Get(pos.X, pos.Y) == ' ' {
+			farthest = pos
+			found = true
+		}
+
+		for _, dir := range []dngn.Position{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}} {
+			next := dngn.Position{X: pos.X + dir.X, Y: pos.Y + dir.Y}
+			if visited[next] || next.X < 0 || next.Y < 0 || next.X >= w.Map.Width || next.Y >= w.Map.Height {
+				continue
+			}
+			visited[next] = true
+
+			if w.Map.Get(next.X, next.Y) != 'x' {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return farthest, found
+}
+
+// salts that give each generator step its own random stream, steps sharing
+// the seed directly would roll the same numbers and pick correlated cells
+const (
+	saltEncounters int64 = iota + 1
+	saltTraps
+	saltDestructible
+	saltBarrels
+)
+
+// featureRand returns the random source of one generator step.
+func featureRand(seed, salt int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed ^ salt*0x5851f42d4c957f2d))
+}
+
+// markDestructible picks inner walls facing the floor to be destructible.
+// The walls around rooms are left alone so a locked room can't be shot open.
+func (w *World) markDestructible(opts MapOptions) {
+	if opts.DestructibleDensity <= 0 {
+		return
+	}
+
+	rng := featureRand(opts.Seed, saltDestructible)
+	w.Destructible = map[dngn.Position]bool{}
+	rings := w.roomRings()
+
+	for y := 1; y < w.Map.Height-1; y++ {
+		for x := 1; x < w.Map.Width-1; x++ {
+			if w.Map.Get(x, y) != 'x' {
+				continue
+			}
+			if w.Map.Get(x+1, y) == 'x' && w.Map.Get(x-1, y) == 'x' && w.Map.Get(x, y+1) == 'x' && w.Map.Get(x, y-1) == 'x' {
+				continue
+			}
+			if rings[dngn.Position{X: x, Y: y}] {
+				continue
+			}
+			if rng.Float64() < opts.DestructibleDensity {
+				w.Destructible[dngn.Position{X: x, Y: y}] = true
+			}
+		}
+	}
+}
+
+// roomRings returns the cells on the ring around every room trigger.
+func (w *World) roomRings() map[dngn.Position]bool {
+	rings := map[dngn.Position]bool{}
+	for _, spawn := range w.Spawns {
+		if spawn.Kind != SpawnRoom {
+			continue
+		}
+
+		x0, y0 := spawn.Cell().X, spawn.Cell().Y
+		x1, y1 := x0+int(spawn.W)/config.BlockSize, y0+int(spawn.H)/config.BlockSize
+		for y := y0 - 1; y <= y1; y++ {
+			for x := x0 - 1; x <= x1; x++ {
+				if x == x0-1 || x == x1 || y == y0-1 || y == y1 {
+					rings[dngn.Position{X: x, Y: y}] = true
+				}
+			}
+		}
+	}
+	return rings
+}
+
+// String returns the generated rune grid.
+func (w *World) String() string {
+	return w.Map.DataToString()
 }
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/AndriiPets/FishGame/utils/dngn"
+)
+
+func TestMarkDestructible(t *testing.T) {
+	tests := []struct {
+		name         string
+		room         bool //the room of propsWorld has a trigger
+		cell         dngn.Position
+		destructible bool
+	}{
+		{"corridor wall", true, dngn.Position{X: 10, Y: 7}, true},
+		{"wall inside the rock", true, dngn.Position{X: 10, Y: 3}, false},
+		{"room wall", true, dngn.Position{X: 19, Y: 6}, false},
+		{"bottom room wall", true, dngn.Position{X: 25, Y: 13}, false},
+		{"wall of an area without a room", false, dngn.Position{X: 19, Y: 6}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			world := propsWorld()
+			if tt.room {
+				world.addRoom(20, 5, 10, 8)
+			}
+
+			opts := DefaultMapOptions()
+			opts.DestructibleDensity = 1
+			world.markDestructible(opts)
+
+			if got := world.Destructible[tt.cell]; got != tt.destructible {
+				t.Fatalf("%v destructible %t, want %t", tt.cell, got, tt.destructible)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	path "github.com/quasilyte/pathing"
+)
+
+// path queries run per tick when the pathfinder is not told otherwise
+const DefaultPathBudget = 8
+
+// PathRequest is a path query waiting for its turn, Result is set once Done.
+// Origin, when set, is read as the request is served so the path starts where
+// the asker is by then, it returning false drops the request unanswered.
+type PathRequest struct {
+	From    path.GridCoord
+	To      path.GridCoord
+	Done    bool
+	Dropped bool
+	Result  path.BuildPathResult
+	Origin  func() (x, y float64, ok bool)
+}
+
+// RequestPath queues an A* query between two positions, it is answered by a
+// later ProcessRequests call.
+func (p *PathFinder) RequestPath(startX, startY, endX, endY float64) *PathRequest {
+	req := &PathRequest{
+		From: p.CoordToGrid(startX, startY),
+		To:   p.CoordToGrid(endX, endY),
+	}
+	p.requests = append(p.requests, req)
+
+	return req
+}
+
+// ProcessRequests answers up to Budget queued requests in the order they came
+// in, the rest wait for the next call. Dropped requests do not count against
+// the budget. It returns how many were answered.
+func (p *PathFinder) ProcessRequests() int {
+	answered, n := 0, 0
+	for ; n < len(p.requests) && (p.Budget <= 0 || answered < p.Budget); n++ {
+		req := p.requests[n]
+		if req.Origin != nil {
+			x, y, ok := req.Origin()
+			if !ok {
+				req.Dropped = true
+				continue
+			}
+			req.From = p.CoordToGrid(x, y)
+		}
+
+		req.Result = p.AStar.BuildPath(p.Grid, req.From, req.To, p.Layers)
+		req.Done = true
+		answered++
+	}
+
+	//shift the waiting requests to the front
+	rest := copy(p.requests, p.requests[n:])
+	for i := rest; i < len(p.requests); i++ {
+		p.requests[i] = nil
+	}
+	p.requests = p.requests[:rest]
+
+	return answered
+}
+
+// PendingRequests is the number of queries waiting to be answered.
+func (p *PathFinder) PendingRequests() int {
+	return len(p.requests)
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/quasilyte/pathing"
+)
+
+func openLayout(w, h int) *PathFinder {
+	data := make([][]rune, h)
+	for y := range data {
+		data[y] = make([]rune, w)
+		for x := range data[y] {
+			data[y][x] = '.'
+		}
+	}
+
+	pf := NewPathFinder()
+	pf.GenerateLayout(data, 'x')
+	return pf
+}
+
+func TestProcessRequests(t *testing.T) {
+	gone := func() (float64, float64, bool) { return 0, 0, false }
+	moved := func() (float64, float64, bool) { return 3*32 + 16, 2*32 + 16, true }
+
+	tests := []struct {
+		name     string
+		origins  []func() (float64, float64, bool)
+		answered int
+		pending  int
+		from     pathing.GridCoord
+	}{
+		{"queued position", []func() (float64, float64, bool){nil}, 1, 0, pathing.GridCoord{X: 1, Y: 1}},
+		{"position when served", []func() (float64, float64, bool){moved}, 1, 0, pathing.GridCoord{X: 3, Y: 2}},
+		{"dropped requests are free", []func() (float64, float64, bool){gone, gone, nil}, 1, 0, pathing.GridCoord{X: 1, Y: 1}},
+		{"budget holds the rest", []func() (float64, float64, bool){nil, nil, nil}, 1, 2, pathing.GridCoord{X: 1, Y: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pf := openLayout(8, 8)
+			pf.Budget = 1
+
+			reqs := make([]*PathRequest, len(tt.origins))
+			for i, origin := range tt.origins {
+				reqs[i] = pf.RequestPath(1*32+16, 1*32+16, 6*32+16, 6*32+16)
+				reqs[i].Origin = origin
+			}
+
+			if got := pf.ProcessRequests(); got != tt.answered {
+				t.Fatalf("answered %d, want %d", got, tt.answered)
+			}
+			if got := pf.PendingRequests(); got != tt.pending {
+				t.Fatalf("pending %d, want %d", got, tt.pending)
+			}
+
+			for _, req := range reqs {
+				if req.Done {
+					if req.From != tt.from {
+						t.Errorf("served from %v, want %v", req.From, tt.from)
+					}
+					if req.Result.Steps.Len() == 0 {
+						t.Errorf("no steps to %v", req.To)
+					}
+				}
+				if req.Dropped && req.Done {
+					t.Errorf("dropped request was answered")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkPathRequests(b *testing.B) {
+	pf, starts, goals := benchLevel(b)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		//every agent asks again as soon as its previous request was answered
+		if pending := pf.PendingRequests(); pending < len(starts) {
+			gx, gy := pf.Grid.CoordToPos(goals[i%len(goals)])
+			for _, s := range starts[:len(starts)-pending] {
+				sx, sy := pf.Grid.CoordToPos(s)
+				pf.RequestPath(sx, sy, gx, gy)
+			}
+		}
+		pf.ProcessRequests()
+	}
+}
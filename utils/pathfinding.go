@@ -15,6 +15,10 @@ type PathFinder struct {
 	Layers path.GridLayer
 	BFS    *path.GreedyBFS
 	AStar  *path.AStar
+	Flow   FlowField //distances to the player, shared by every enemy chasing them
+	Budget int       //path requests answered per tick, 0 answers all of them
+
+	requests []*PathRequest
 }
 
 type PathAlgo string
@@ -47,7 +51,7 @@ func NewPathFinder() *PathFinder {
 		NumRows: uint(grid.NumRows()),
 	})
 
-	return &PathFinder{Grid: grid, BFS: bfs, AStar: aStar}
+	return &PathFinder{Grid: grid, BFS: bfs, AStar: aStar, Budget: DefaultPathBudget}
 }
 
 func (p *PathFinder) GenerateLayout(data [][]rune, wall rune) {
@@ -78,6 +82,19 @@ func (p *PathFinder) GenerateLayout(data [][]rune, wall rune) {
 	//})
 
 	p.Layers = groundltLayer
+	p.Flow.Valid = false
+}
+
+// SetWalkable opens or closes a cell of a generated layout, the flow field is
+// rebuilt on its next update.
+func (p *PathFinder) SetWalkable(c path.GridCoord, walkable bool) {
+	tile := uint8(TileWall)
+	if walkable {
+		tile = TileFloor
+	}
+
+	p.Grid.SetCellTile(c, tile)
+	p.Flow.Valid = false
 }
 
 func (p *PathFinder) MakePath(startX, startY, endX, endY float64, algo PathAlgo) path.BuildPathResult {
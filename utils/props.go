@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/utils/dngn"
+)
+
+// placeBarrels puts explosive barrels against the walls away from the start,
+// leaving corridors free, and marks their tiles as taken.
+func (w *World) placeBarrels(start dngn.Position, occupied map[dngn.Position]bool, opts MapOptions) {
+	if opts.BarrelDensity <= 0 {
+		return
+	}
+
+	rng := featureRand(opts.Seed, saltBarrels)
+
+	for y := 0; y < w.Map.Height; y++ {
+		for x := 0; x < w.Map.Width; x++ {
+			pos := dngn.Position{X: x, Y: y}
+			if w.Map.Get(x, y) != ' ' || occupied[pos] || start.DistanceTo(pos) <= enemySafeRadius {
+				continue
+			}
+			right, left, down, up := w.Map.Get(x+1, y) == 'x', w.Map.Get(x-1, y) == 'x', w.Map.Get(x, y+1) == 'x', w.Map.Get(x, y-1) == 'x'
+			if !(right || left || down || up) {
+				continue
+			}
+			//corridors stay clear
+			if (right && left) || (down && up) {
+				continue
+			}
+			if rng.Float64() >= opts.BarrelDensity {
+				continue
+			}
+
+			occupied[pos] = true
+			w.Spawns = append(w.Spawns, Spawn{
+				Kind: SpawnBarrel,
+				X:    float64(x * config.BlockSize),
+				Y:    float64(y * config.BlockSize),
+			})
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/AndriiPets/FishGame/utils/dngn"
+)
+
+// propsWorld is a room at 20..29, 5..12 with a corridor running into its left side on row 8.
+func propsWorld() *World {
+	world := NewWorldMap()
+	world.Map.Select().Fill('x')
+	world.Map.Select().FilterByArea(20, 5, 10, 8).Fill(' ')
+	world.Map.Select().FilterByArea(5, 8, 15, 1).Fill(' ')
+	return world
+}
+
+func TestPlaceBarrels(t *testing.T) {
+	far := dngn.Position{X: 1, Y: 1}
+
+	tests := []struct {
+		name     string
+		start    dngn.Position
+		occupied []dngn.Position
+		cell     dngn.Position
+		barrel   bool
+	}{
+		{"corridor stays clear", far, nil, dngn.Position{X: 10, Y: 8}, false},
+		{"along a room wall", far, nil, dngn.Position{X: 25, Y: 5}, true},
+		{"room corner", far, nil, dngn.Position{X: 20, Y: 5}, true},
+		{"middle of the room", far, nil, dngn.Position{X: 25, Y: 9}, false},
+		{"next to the start", dngn.Position{X: 25, Y: 7}, nil, dngn.Position{X: 25, Y: 5}, false},
+		{"taken tile", far, []dngn.Position{{X: 25, Y: 5}}, dngn.Position{X: 25, Y: 5}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			world := propsWorld()
+			occupied := map[dngn.Position]bool{}
+			for _, pos := range tt.occupied {
+				occupied[pos] = true
+			}
+
+			opts := DefaultMapOptions()
+			opts.BarrelDensity = 1
+			world.placeBarrels(tt.start, occupied, opts)
+
+			found := false
+			for _, spawn := range world.Spawns {
+				if spawn.Kind == SpawnBarrel && spawn.Cell() == tt.cell {
+					found = true
+				}
+			}
+			if found != tt.barrel {
+				t.Fatalf("barrel at %v %t, want %t", tt.cell, found, tt.barrel)
+			}
+		})
+	}
+}
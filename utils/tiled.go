@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/utils/dngn"
+)
+
+type SpawnKind string
+
+const (
+	SpawnPlayer SpawnKind = "player"
+	SpawnEnemy  SpawnKind = "enemy"
+	SpawnPickup SpawnKind = "pickup"
+	SpawnExit   SpawnKind = "exit"
+	SpawnRoom   SpawnKind = "room"
+	SpawnTrap   SpawnKind = "trap"
+	SpawnPrompt SpawnKind = "prompt"
+	SpawnBarrel SpawnKind = "barrel"
+)
+
+// Spawn is an entity placed by a level in world coordinates.
+type Spawn struct {
+	Kind SpawnKind
+	Type string //enemy type or pickup item, empty for the default one
+	X, Y float64
+	W, H float64 //size of area spawns, zero for points
+	Text string  //what a prompt says
+}
+
+// Subset of the Tiled JSON map format, see https://doc.mapeditor.org/en/stable/reference/json-map-format/
+type tiledMap struct {
+	Width      int          `json:"width"`
+	Height     int          `json:"height"`
+	TileWidth  int          `json:"tilewidth"`
+	TileHeight int          `json:"tileheight"`
+	Infinite   bool         `json:"infinite"`
+	Layers     []tiledLayer `json:"layers"`
+}
+
+type tiledLayer struct {
+	Name       string          `json:"name"`
+	Type       string          `json:"type"`
+	Width      int             `json:"width"`
+	Height     int             `json:"height"`
+	Data       []uint32        `json:"data"`
+	Objects    []tiledObject   `json:"objects"`
+	Properties []tiledProperty `json:"properties"`
+}
+
+type tiledObject struct {
+	ID         int             `json:"id"`
+	Name       string          `json:"name"`
+	Type       string          `json:"type"`
+	Class      string          `json:"class"` //Tiled 1.9+ renamed type to class
+	X          float64         `json:"x"`
+	Y          float64         `json:"y"`
+	Width      float64         `json:"width"`
+	Height     float64         `json:"height"`
+	Properties []tiledProperty `json:"properties"`
+}
+
+type tiledProperty struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// tile ids carry the flip flags in the highest bits
+const tiledFlipMask = 0xF0000000
+
+func property(props []tiledProperty, name string) (interface{}, bool) {
+	for _, p := range props {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return nil, false
+}
+
+// isWallLayer reports whether a tile layer blocks movement, either by being
+// named "walls" or by having a boolean "collision" property.
+func isWallLayer(l tiledLayer) bool {
+	if v, ok := property(l.Properties, "collision"); ok {
+		b, _ := v.(bool)
+		return b
+	}
+	return strings.EqualFold(l.Name, "walls")
+}
+
+// LoadTiledMap converts a Tiled JSON map into a World. Tiles of wall layers
+// become 'x' runes, everything else is floor. Objects of type player, enemy,
+// pickup and barrel become spawns; the "type" property selects the enemy or pickup type.
+// Rectangles of type room, trap and prompt become trigger areas, a prompt shows
+// its "text" property.
+func LoadTiledMap(data []byte) (*World, error) {
+	tm := &tiledMap{}
+	if err := json.Unmarshal(data, tm); err != nil {
+		return nil, err
+	}
+
+	if tm.Infinite {
+		return nil, fmt.Errorf("infinite tiled maps are not supported")
+	}
+	if tm.Width > config.MapWidth || tm.Height > config.MapHeigth {
+		return nil, fmt.Errorf("map is %dx%d, at most %dx%d is supported", tm.Width, tm.Height, config.MapWidth, config.MapHeigth)
+	}
+	if tm.TileWidth <= 0 || tm.TileHeight <= 0 {
+		return nil, fmt.Errorf("invalid tile size %dx%d", tm.TileWidth, tm.TileHeight)
+	}
+
+	world := NewWorldMap()
+
+	//anything outside of the authored area is solid
+	world.Map.Select().Fill('x')
+	world.Map.Select().FilterByArea(0, 0, tm.Width, tm.Height).Fill(' ')
+
+	//object coordinates are in tiled pixels, scale them to the game blocks
+	scaleX := float64(config.BlockSize) / float64(tm.TileWidth)
+	scaleY := float64(config.BlockSize) / float64(tm.TileHeight)
+
+	players := 0
+
+	for _, l := range tm.Layers {
+		switch l.Type {
+		case "tilelayer":
+			if !isWallLayer(l) {
+				continue
+			}
+			if len(l.Data) != l.Width*l.Height {
+				return nil, fmt.Errorf("layer %q: expected %d tiles, got %d (only csv encoding is supported)", l.Name, l.Width*l.Height, len(l.Data))
+			}
+
+			for i, gid := range l.Data {
+				if gid&^tiledFlipMask == 0 {
+					continue
+				}
+				world.Map.Set(i%l.Width, i/l.Width, 'x')
+			}
+
+		case "objectgroup":
+			for _, o := range l.Objects {
+				kind := o.Type
+				if kind == "" {
+					kind = o.Class
+				}
+
+				spawn := Spawn{
+					Kind: SpawnKind(kind),
+					X:    o.X * scaleX,
+					Y:    o.Y * scaleY,
+					W:    o.Width * scaleX,
+					H:    o.Height * scaleY,
+				}
+
+				if v, ok := property(o.Properties, "type"); ok {
+					s, ok := v.(string)
+					if !ok {
+						return nil, fmt.Errorf("object %d %q: type property must be a string", o.ID, o.Name)
+					}
+					spawn.Type = s
+				}
+
+				if v, ok := property(o.Properties, "text"); ok {
+					s, ok := v.(string)
+					if !ok {
+						return nil, fmt.Errorf("object %d %q: text property must be a string", o.ID, o.Name)
+					}
+					spawn.Text = s
+				}
+
+				switch spawn.Kind {
+				case SpawnPlayer:
+					players++
+				case SpawnEnemy, SpawnPickup, SpawnExit, SpawnBarrel:
+				case SpawnRoom, SpawnTrap, SpawnPrompt:
+					if spawn.W <= 0 || spawn.H <= 0 {
+						return nil, fmt.Errorf("object %d %q: a %s needs a width and height", o.ID, o.Name, kind)
+					}
+				default:
+					return nil, fmt.Errorf("object %d %q: unknown object type %q", o.ID, o.Name, kind)
+				}
+
+				world.Spawns = append(world.Spawns, spawn)
+			}
+		}
+	}
+
+	if players != 1 {
+		return nil, fmt.Errorf("map must have exactly one player spawn, found %d", players)
+	}
+
+	return world, nil
+}
+
+// Cell returns the layout cell a spawn is placed in.
+func (s Spawn) Cell() dngn.Position {
+	return dngn.Position{X: int(s.X) / config.BlockSize, Y: int(s.Y) / config.BlockSize}
+}
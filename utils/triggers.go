@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"github.com/AndriiPets/FishGame/config"
+	"github.com/AndriiPets/FishGame/utils/dngn"
+)
+
+// addRoom places the trigger of a room covering an area of cells, its doors
+// are found on the floor around it when it locks.
+func (w *World) addRoom(x, y, width, height int) {
+	w.Spawns = append(w.Spawns, Spawn{
+		Kind: SpawnRoom,
+		X:    float64(x * config.BlockSize),
+		Y:    float64(y * config.BlockSize),
+		W:    float64(width * config.BlockSize),
+		H:    float64(height * config.BlockSize),
+	})
+}
+
+// placeTraps scatters spike traps over the free floor away from the start and
+// marks their tiles as taken.
+func (w *World) placeTraps(start dngn.Position, occupied map[dngn.Position]bool, opts MapOptions) {
+	if opts.TrapDensity <= 0 {
+		return
+	}
+
+	rng := featureRand(opts.Seed, saltTraps)
+
+	for y := 0; y < w.Map.Height; y++ {
+		for x := 0; x < w.Map.Width; x++ {
+			pos := dngn.Position{X: x, Y: y}
+			if w.Map.Get(x, y) != ' ' || occupied[pos] || start.DistanceTo(pos) <= enemySafeRadius {
+				continue
+			}
+			if rng.Float64() >= opts.TrapDensity {
+				continue
+			}
+
+			occupied[pos] = true
+			w.Spawns = append(w.Spawns, Spawn{
+				Kind: SpawnTrap,
+				X:    float64(x * config.BlockSize),
+				Y:    float64(y * config.BlockSize),
+				W:    float64(config.BlockSize),
+				H:    float64(config.BlockSize),
+			})
+		}
+	}
+}